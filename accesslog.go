@@ -0,0 +1,199 @@
+package goblog
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The formats AccessLogHandler may write.
+const (
+	// the Common Log Format.
+	LogCommon = "common"
+	// the Combined Log Format, adding the referer and
+	// user agent to LogCommon.
+	LogCombined = "combined"
+	// one JSON object per line.
+	LogJSON = "json"
+)
+
+// requestIDHeader assigns an identifier to a request
+// which may be correlated across services.
+const requestIDHeader = "X-Request-ID"
+
+// AccessLogFormats lists the formats AccessLogHandler
+// accepts.
+var AccessLogFormats = []string{LogCommon, LogCombined, LogJSON}
+
+// accessLogEntry is a single access log line.
+type accessLogEntry struct {
+	Time       time.Time `json:"time"`
+	RequestID  string    `json:"request_id"`
+	RemoteAddr string    `json:"remote_addr"`
+	Method     string    `json:"method"`
+	URI        string    `json:"uri"`
+	Proto      string    `json:"proto"`
+	Status     int       `json:"status"`
+	Bytes      int64     `json:"bytes"`
+	DurationMS float64   `json:"duration_ms"`
+	Referer    string    `json:"referer,omitempty"`
+	UserAgent  string    `json:"user_agent,omitempty"`
+}
+
+// AccessLogHandler writes a line to w in the provided format for
+// each request served by h.
+//
+// Each request is assigned an X-Request-ID, unless the client
+// provided one, which is set on both the request passed to h and
+// the response. Common and combined lines are followed by the
+// request ID and the latency in milliseconds.
+func AccessLogHandler(w io.Writer, format string, h http.Handler) (http.Handler, error) {
+	switch format {
+	case LogCommon, LogCombined, LogJSON:
+	default:
+		return nil, fmt.Errorf("unknown access log format %q, must be one of %v", format, AccessLogFormats)
+	}
+
+	var mu sync.Mutex
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		id := r.Header.Get(requestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+			r.Header.Set(requestIDHeader, id)
+		}
+		rw.Header().Set(requestIDHeader, id)
+
+		sw := &statusWriter{ResponseWriter: rw}
+		h.ServeHTTP(sw, r)
+		if sw.status == 0 {
+			sw.status = http.StatusOK
+		}
+
+		entry := accessLogEntry{
+			Time:       start,
+			RequestID:  id,
+			RemoteAddr: remoteHost(r),
+			Method:     r.Method,
+			URI:        r.RequestURI,
+			Proto:      r.Proto,
+			Status:     sw.status,
+			Bytes:      sw.bytes,
+			DurationMS: float64(time.Since(start).Microseconds()) / 1000,
+			Referer:    r.Referer(),
+			UserAgent:  r.UserAgent(),
+		}
+		line := formatEntry(entry, format)
+
+		mu.Lock()
+		defer mu.Unlock()
+		w.Write(line)
+	}), nil
+}
+
+// formatEntry returns the newline terminated log line
+// for entry.
+func formatEntry(e accessLogEntry, format string) []byte {
+	if format == LogJSON {
+		b, _ := json.Marshal(e)
+		return append(b, '\n')
+	}
+
+	bytes := "-"
+	if e.Bytes > 0 {
+		bytes = fmt.Sprint(e.Bytes)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, `%s - - [%s] "%s %s %s" %d %s`,
+		e.RemoteAddr, e.Time.Format("02/Jan/2006:15:04:05 -0700"),
+		e.Method, e.URI, e.Proto, e.Status, bytes)
+	if format == LogCombined {
+		fmt.Fprintf(&b, ` %q %q`, orDash(e.Referer), orDash(e.UserAgent))
+	}
+	fmt.Fprintf(&b, " %s %.3f\n", e.RequestID, e.DurationMS)
+	return []byte(b.String())
+}
+
+// orDash returns s or "-" if s is empty, as the log
+// formats expect for missing fields.
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// remoteHost returns the host of the request's remote
+// address or "-" if it has none, such as requests over
+// a Unix socket.
+func remoteHost(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	if host == "@" {
+		return "-"
+	}
+	return orDash(host)
+}
+
+// validRequestID reports whether a client provided request
+// ID is safe to propagate and log.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 128 {
+		return false
+	}
+	for _, c := range id {
+		if c <= ' ' || c > '~' || c == '"' {
+			return false
+		}
+	}
+	return true
+}
+
+// newRequestID returns a random request ID.
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// statusWriter is a http.ResponseWriter recording the
+// status and number of bytes of the response.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+func (s *statusWriter) WriteHeader(code int) {
+	if s.status == 0 {
+		s.status = code
+	}
+	s.ResponseWriter.WriteHeader(code)
+}
+
+func (s *statusWriter) Write(b []byte) (int, error) {
+	if s.status == 0 {
+		s.status = http.StatusOK
+	}
+	n, err := s.ResponseWriter.Write(b)
+	s.bytes += int64(n)
+	return n, err
+}
+
+// Flush implements http.Flusher when the underlying
+// http.ResponseWriter does.
+func (s *statusWriter) Flush() {
+	if f, ok := s.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
@@ -0,0 +1,96 @@
+package goblog
+
+import (
+	"mime"
+	"net/http"
+	"path"
+)
+
+const (
+	// the Cache-Control directives used when the
+	// CachePolicy leaves them empty.
+	defaultHashedCacheControl  = "public, max-age=31536000, immutable"
+	defaultIndexCacheControl   = "no-cache"
+	defaultDefaultCacheControl = "public, max-age=3600"
+)
+
+// CachePolicy configures the Cache-Control headers GoBlog
+// serves with web root and post assets.
+//
+// Empty fields fall back to sensible defaults.
+type CachePolicy struct {
+	// Cache-Control for assets fingerprinted by 'goblog build',
+	// ones with a content hash in their names such as
+	// "app.3f2a9c1b.js". See Config.Fingerprint.
+	//
+	// Defaults to a year long immutable max-age.
+	Hashed string
+	// Cache-Control for the web root's index.html, including
+	// when served for front-end application paths.
+	//
+	// Defaults to "no-cache", forcing revalidation.
+	Index string
+	// Cache-Control for all other assets.
+	//
+	// Defaults to an hour long max-age.
+	Default string
+}
+
+// extraTypes supplements the mime package's built in table,
+// which may be all that's available in minimal containers.
+var extraTypes = map[string]string{
+	".ico":         "image/x-icon",
+	".map":         "application/json",
+	".md":          "text/markdown; charset=utf-8",
+	".otf":         "font/otf",
+	".ttf":         "font/ttf",
+	".txt":         "text/plain; charset=utf-8",
+	".webmanifest": "application/manifest+json",
+	".woff":        "font/woff",
+	".woff2":       "font/woff2",
+}
+
+func init() {
+	for ext, typ := range extraTypes {
+		if mime.TypeByExtension(ext) == "" {
+			mime.AddExtensionType(ext, typ)
+		}
+	}
+}
+
+// contentType determines the Content-Type of the file name
+// from its extension, sniffing its contents b otherwise.
+func contentType(name string, b []byte) string {
+	if ctype := mime.TypeByExtension(path.Ext(name)); ctype != "" {
+		return ctype
+	}
+	return http.DetectContentType(b)
+}
+
+// cacheControl returns the Cache-Control the configured
+// CachePolicy assigns to the file name.
+//
+// index indicates the web root's index.html is being served.
+func cacheControl(name string, index bool) string {
+	policy := Conf.Cache
+	switch {
+	case local():
+		// local content changes between requests.
+		return "no-cache"
+	case index:
+		if policy.Index != "" {
+			return policy.Index
+		}
+		return defaultIndexCacheControl
+	case fingerprinted(name):
+		if policy.Hashed != "" {
+			return policy.Hashed
+		}
+		return defaultHashedCacheControl
+	default:
+		if policy.Default != "" {
+			return policy.Default
+		}
+		return defaultDefaultCacheControl
+	}
+}
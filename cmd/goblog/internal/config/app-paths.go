@@ -37,20 +37,30 @@ Example: /post,/archive,/settings
 	paths := strings.Split(list, ",")
 	golog.Info("Adding the following paths: %v\n", paths)
 
-	goblog.Conf.AppPaths = paths
+	writeConfig(func(conf *goblog.Config) {
+		conf.AppPaths = paths
+	})
+}
 
-	dest := path.Join(goblog.Home, "src/config/config.yaml")
-	if _, err := os.Stat(dest); err != nil {
-		golog.Fatal("Could not stat config: %v", err)
+// writeConfig applies update to the local config.yaml.
+//
+// The local config is updated rather than the embedded one,
+// it may hold edits which are not yet built.
+func writeConfig(update func(*goblog.Config)) {
+	conf, err := goblog.LocalConfig()
+	if err != nil {
+		golog.Fatal("Could not read config: %v", err)
 	}
+	update(&conf)
 
+	dest := path.Join(goblog.Configs, "config.yaml")
 	f, err := os.OpenFile(dest, os.O_RDWR|os.O_TRUNC, 0)
 	if err != nil {
 		golog.Fatal("Failed to open config.yaml: %v", err)
 	}
 	defer f.Close()
 
-	if err = yaml.NewEncoder(f).Encode(&goblog.Conf); err != nil {
+	if err = yaml.NewEncoder(f).Encode(&conf); err != nil {
 		golog.Fatal("Failed to write config: %v", err)
 	}
 	golog.Info("Wrote new config to %v\n", dest)
@@ -0,0 +1,92 @@
+package config
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/ldelossa/goblog"
+	"github.com/ldelossa/goblog/pkg/golog"
+)
+
+func corsPolicy(ctx context.Context) {
+	color.Blue(`
+Configure which cross-origin requests GoBlog allows.
+
+Lists are comma separated. Leave a prompt empty to accept its default.
+`)
+	in := bufio.NewReader(os.Stdin)
+	var policy goblog.CORSPolicy
+
+	policy.AllowedOrigins = scanList(in, `
+Allowed origins, an origin may contain a single '*' wildcard.
+Default: every origin
+
+Example: https://blog.example.com,https://*.example.com
+`)
+	policy.AllowedMethods = scanList(in, `
+Allowed methods.
+Default: GET,HEAD,POST
+`)
+	policy.AllowedHeaders = scanList(in, `
+Allowed request headers.
+Default: none
+`)
+	policy.ExposedHeaders = scanList(in, `
+Response headers exposed to your front-end.
+Default: X-Total-Count,Link
+`)
+	policy.AllowCredentials = scanLine(in, `
+Allow credentials such as cookies? [y/N]
+`) == "y"
+	if policy.AllowCredentials && len(policy.AllowedOrigins) == 0 {
+		golog.Fatal("Allowing credentials requires a list of allowed origins")
+	}
+	if tmp := scanLine(in, `
+Seconds a preflight response may be cached.
+Default: 0, left to the client
+`); tmp != "" {
+		age, err := strconv.Atoi(tmp)
+		if err != nil || age < 0 {
+			golog.Fatal("Could not parse max age: %v", tmp)
+		}
+		policy.MaxAge = age
+	}
+	policy.Paths = scanList(in, `
+Paths the policy applies to, other paths allow every origin.
+Default: every path
+
+Example: /summaries,/posts/
+`)
+
+	golog.Info("Setting the following CORS policy: %+v\n", policy)
+	writeConfig(func(conf *goblog.Config) {
+		conf.CORS = policy
+	})
+}
+
+// scanLine prints prompt and returns the trimmed line
+// read from in.
+func scanLine(in *bufio.Reader, prompt string) string {
+	color.Blue(prompt)
+	line, err := in.ReadString('\n')
+	if err != nil && line == "" {
+		golog.Fatal("failed to scan input: %v", err)
+	}
+	return strings.TrimSpace(line)
+}
+
+// scanList prints prompt and returns the comma separated
+// list read from in.
+func scanList(in *bufio.Reader, prompt string) []string {
+	var list []string
+	for _, item := range strings.Split(scanLine(in, prompt), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
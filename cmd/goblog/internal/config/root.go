@@ -14,6 +14,7 @@ These configs are embedded into the GoBlog binary.
 If you're changing a config option you'll need to rebuild GoBlog.
 
 goblog config app-paths  - specify your web applicatoin's
+goblog config cors       - specify which cross-origin requests are allowed
 goblog config fork       - update your goblog fork
 `
 
@@ -31,6 +32,8 @@ func Root(ctx context.Context) {
 	switch os.Args[2] {
 	case "app-paths":
 		appPaths(ctx)
+	case "cors":
+		corsPolicy(ctx)
 	case "fork":
 	}
 }
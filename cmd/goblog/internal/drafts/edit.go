@@ -6,13 +6,11 @@ import (
 	"fmt"
 	"os"
 	"path"
-	"strconv"
 
 	"github.com/ldelossa/goblog"
 	"github.com/ldelossa/goblog/cmd/goblog/internal/initialize"
 	"github.com/ldelossa/goblog/pkg/golog"
 	"github.com/ldelossa/goblog/ui"
-	"gopkg.in/yaml.v3"
 )
 
 var editFS = flag.NewFlagSet("edit", flag.ExitOnError)
@@ -27,8 +25,12 @@ The edit subcommand opens a draft for editing.
 
 The '--meta' flag may be used to edit both the contents and metadata of a post in yaml syntax.
 
+A draft is dated when it is first published. Editing records the updated time and keeps the
+date of a post moved back to drafts, so it keeps its place in date order. The '--touch' flag
+moves the publish date to now.
+
 Usage:
-	goblog drafts edit ID [--meta]
+	goblog drafts edit SLUG|ID [--meta] [--touch]
 
 `)
 	}
@@ -40,17 +42,16 @@ Usage:
 		return fmt.Errorf("Error: Not enough arguments provided to 'edit' subcommand\n")
 	}
 
-	// first arg must be id
-	id, err := strconv.Atoi(os.Args[3])
-	if err != nil {
-		editFS.Usage()
-		return fmt.Errorf("Error: first argument to 'view' subcommand must be an integer id")
-	}
+	// first arg is a slug or id
+	ref := os.Args[3]
 
-	var meta bool
+	var meta, touch bool
 	for _, arg := range os.Args {
-		if arg == "--meta" || arg == "-meta" {
+		switch arg {
+		case "--meta", "-meta":
 			meta = true
+		case "--touch", "-touch":
+			touch = true
 		}
 	}
 
@@ -60,17 +61,11 @@ Usage:
 		return nil
 	}
 
-	if id == 0 {
-		editFS.Usage()
-		return fmt.Errorf("Error: must provide a draft id.")
-	}
-
-	if id > len(sorted) {
-		return fmt.Errorf("Error: draft id %d does not exist", id)
+	draft, err := sorted.Lookup(ref)
+	if err != nil {
+		return fmt.Errorf("Error: %v", err)
 	}
 
-	draft := sorted[id-1]
-
 	editor, err := ui.NewEditor(ctx, &draft)
 	if err != nil {
 		golog.Fatal("%v", err)
@@ -95,6 +90,12 @@ Usage:
 	publish, err := ui.GlobalPrompter.ShouldPublishPost(ctx)
 	onErrDumpMarkdown(draft.MarkDown.Value, err)
 
+	// a post is dated when it is first published,
+	// editing only records the updated time.
+	if touch || publish && draft.Published.IsZero() {
+		draft.Published = draft.Updated
+	}
+
 	// draft.Path will already be formated and have
 	// .post syntax
 	formated := path.Base(draft.Path)
@@ -109,7 +110,7 @@ Usage:
 	onErrDumpMarkdown(draft.MarkDown.Value, err)
 	defer f.Close()
 
-	err = yaml.NewEncoder(f).Encode(draft)
+	err = goblog.EncodePost(f, draft)
 	onErrDumpMarkdown(draft.MarkDown.Value, err)
 
 	golog.Info(`Your draft has been written to: %v.`, postPath)
@@ -18,7 +18,10 @@ var listFlags = struct {
 func list(ctx context.Context) error {
 	listFS.Usage = func() {
 		fmt.Printf(`
-The list subcommand lists drafts and their ids. 
+The list subcommand lists drafts with their slugs and ids.
+
+Other drafts subcommands accept either. A slug never changes, an id is the draft's position
+in this list and changes as drafts are added or edited.
 
 If the "--embedded" flag is provided only drafts embedded into the current GoBlog binary will be displayed.
 
@@ -57,13 +60,13 @@ Usage:
 
 	tw := tabwriter.NewWriter(os.Stdout, 0, 8, 1, '\t', 0)
 	if embedded {
-		fmt.Fprintln(tw, "ID\tDATE\tTITLE\tSUMMARY\t(embedded)")
+		fmt.Fprintln(tw, "ID\tSLUG\tDATE\tTITLE\tSUMMARY\t(embedded)")
 	} else {
-		fmt.Fprintln(tw, "ID\tDATE\tTITLE\tSUMMARY\t(local)")
+		fmt.Fprintln(tw, "ID\tSLUG\tDATE\tTITLE\tSUMMARY\t(local)")
 	}
 
 	for i, draft := range sorted {
-		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\n", i+1, draft.Date.Format("2006-Jan-2"), draft.Title, draft.Summary)
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\n", i+1, draft.Slug, draft.Date().Format("2006-Jan-2"), draft.Title, draft.Summary)
 	}
 	err = tw.Flush()
 	if err != nil {
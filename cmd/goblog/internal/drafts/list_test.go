@@ -14,7 +14,7 @@ import (
 // Sanity check.
 func TestDraftsListControl(t *testing.T) {
 	const (
-		want = "ID\tDATE\tTITLE\tSUMMARY\n"
+		want = "ID\tSLUG\tDATE\tTITLE\tSUMMARY\n"
 	)
 	goblog.LocalPostsCache = []goblog.Post{}
 	goblog.EmbeddedPostsCache = []goblog.Post{}
@@ -39,15 +39,15 @@ func TestDraftsListLocal(t *testing.T) {
 	}{
 		{Name: "1 Post Listing",
 			N:    1,
-			Want: "ID\tDATE\t\tTITLE\tSUMMARY\n1\t2021-May-28\t1\t1\n",
+			Want: "ID\tSLUG\tDATE\t\tTITLE\tSUMMARY\n1\tpost-1\t2021-May-28\t1\t1\n",
 		},
 		{Name: "5 Post Listing",
 			N:    5,
-			Want: "ID\tDATE\t\tTITLE\tSUMMARY\n1\t2021-May-28\t5\t5\n2\t2021-May-27\t4\t4\n3\t2021-May-26\t3\t3\n4\t2021-May-25\t2\t2\n5\t2021-May-24\t1\t1\n",
+			Want: "ID\tSLUG\tDATE\t\tTITLE\tSUMMARY\n1\tpost-5\t2021-May-28\t5\t5\n2\tpost-4\t2021-May-27\t4\t4\n3\tpost-3\t2021-May-26\t3\t3\n4\tpost-2\t2021-May-25\t2\t2\n5\tpost-1\t2021-May-24\t1\t1\n",
 		},
 		{Name: "10 Post Listing",
 			N:    10,
-			Want: "ID\tDATE\t\tTITLE\tSUMMARY\n1\t2021-May-28\t10\t10\n2\t2021-May-27\t9\t9\n3\t2021-May-26\t8\t8\n4\t2021-May-25\t7\t7\n5\t2021-May-24\t6\t6\n6\t2021-May-23\t5\t5\n7\t2021-May-22\t4\t4\n8\t2021-May-21\t3\t3\n9\t2021-May-20\t2\t2\n10\t2021-May-19\t1\t1\n",
+			Want: "ID\tSLUG\tDATE\t\tTITLE\tSUMMARY\n1\tpost-10\t2021-May-28\t10\t10\n2\tpost-9\t2021-May-27\t9\t9\n3\tpost-8\t2021-May-26\t8\t8\n4\tpost-7\t2021-May-25\t7\t7\n5\tpost-6\t2021-May-24\t6\t6\n6\tpost-5\t2021-May-23\t5\t5\n7\tpost-4\t2021-May-22\t4\t4\n8\tpost-3\t2021-May-21\t3\t3\n9\tpost-2\t2021-May-20\t2\t2\n10\tpost-1\t2021-May-19\t1\t1\n",
 		},
 	}
 
@@ -73,15 +73,15 @@ func TestDraftsListEmbedded(t *testing.T) {
 	}{
 		{Name: "1 Post Listing",
 			N:    1,
-			Want: "ID\tDATE\t\tTITLE\tSUMMARY\n1\t2021-May-28\t1\t1\n",
+			Want: "ID\tSLUG\tDATE\t\tTITLE\tSUMMARY\n1\tpost-1\t2021-May-28\t1\t1\n",
 		},
 		{Name: "5 Post Listing",
 			N:    5,
-			Want: "ID\tDATE\t\tTITLE\tSUMMARY\n1\t2021-May-28\t5\t5\n2\t2021-May-27\t4\t4\n3\t2021-May-26\t3\t3\n4\t2021-May-25\t2\t2\n5\t2021-May-24\t1\t1\n",
+			Want: "ID\tSLUG\tDATE\t\tTITLE\tSUMMARY\n1\tpost-5\t2021-May-28\t5\t5\n2\tpost-4\t2021-May-27\t4\t4\n3\tpost-3\t2021-May-26\t3\t3\n4\tpost-2\t2021-May-25\t2\t2\n5\tpost-1\t2021-May-24\t1\t1\n",
 		},
 		{Name: "10 Post Listing",
 			N:    10,
-			Want: "ID\tDATE\t\tTITLE\tSUMMARY\n1\t2021-May-28\t10\t10\n2\t2021-May-27\t9\t9\n3\t2021-May-26\t8\t8\n4\t2021-May-25\t7\t7\n5\t2021-May-24\t6\t6\n6\t2021-May-23\t5\t5\n7\t2021-May-22\t4\t4\n8\t2021-May-21\t3\t3\n9\t2021-May-20\t2\t2\n10\t2021-May-19\t1\t1\n",
+			Want: "ID\tSLUG\tDATE\t\tTITLE\tSUMMARY\n1\tpost-10\t2021-May-28\t10\t10\n2\tpost-9\t2021-May-27\t9\t9\n3\tpost-8\t2021-May-26\t8\t8\n4\tpost-7\t2021-May-25\t7\t7\n5\tpost-6\t2021-May-24\t6\t6\n6\tpost-5\t2021-May-23\t5\t5\n7\tpost-4\t2021-May-22\t4\t4\n8\tpost-3\t2021-May-21\t3\t3\n9\tpost-2\t2021-May-20\t2\t2\n10\tpost-1\t2021-May-19\t1\t1\n",
 		},
 	}
 
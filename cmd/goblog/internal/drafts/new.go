@@ -12,7 +12,6 @@ import (
 	"github.com/ldelossa/goblog/cmd/goblog/internal/initialize"
 	"github.com/ldelossa/goblog/pkg/golog"
 	"github.com/ldelossa/goblog/ui"
-	"gopkg.in/yaml.v3"
 )
 
 var newFS = flag.NewFlagSet("new", flag.ExitOnError)
@@ -66,6 +65,7 @@ Usage:
 	var postPath string
 	base := filepath.Base(draft.Path)
 	if publish {
+		draft.Published = draft.Updated
 		postPath = path.Join(goblog.Posts, base)
 	} else {
 		postPath = path.Join(goblog.Drafts, base)
@@ -75,7 +75,7 @@ Usage:
 	onErrDumpMarkdown(draft.MarkDown.Value, err)
 	defer f.Close()
 
-	err = yaml.NewEncoder(f).Encode(draft)
+	err = goblog.EncodePost(f, draft)
 	onErrDumpMarkdown(draft.MarkDown.Value, err)
 
 	golog.Info(`Your draft has been written to: %v
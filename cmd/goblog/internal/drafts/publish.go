@@ -7,7 +7,7 @@ import (
 	"os"
 	"path"
 	"path/filepath"
-	"strconv"
+	"time"
 
 	"github.com/ldelossa/goblog"
 	"github.com/ldelossa/goblog/pkg/golog"
@@ -24,8 +24,11 @@ The publish subcommand publishes an existing draft.
 
 When a draft is published it will be embedded into the next GoBlog binary created by running 'goblog publish'.
 
+A draft is dated when it is first published, not when it was started. A post moved back
+to drafts with 'goblog posts draft' keeps its original date.
+
 Usage:
-	goblog drafts publish ID
+	goblog drafts publish SLUG|ID
 
 `)
 	}
@@ -38,38 +41,65 @@ Usage:
 		golog.Error("Error: Not enough arguments provided to 'edit' subcommand\n")
 	}
 
-	// first arg must be id
-	id, err := strconv.Atoi(os.Args[3])
-	if err != nil {
-		return fmt.Errorf("Error: first argument to 'publish' subcommand must be an integer id")
-	}
+	// first arg is a slug or id
+	ref := os.Args[3]
 
 	sorted := goblog.LocalDraftsCache
-	if err != nil {
-		return fmt.Errorf("Error: failed retrieving drafts: %v", err)
-	}
 	if len(sorted) == 0 {
 		golog.Info("There are no drafts to edit currently.\nUse 'goblog drafts new' to create one.")
 	}
 
-	if id == 0 {
-		return fmt.Errorf("Error: must provide a draft id.")
+	draft, err := sorted.Lookup(ref)
+	if err != nil {
+		return fmt.Errorf("Error: %v", err)
 	}
 
-	if id > len(sorted) {
-		return fmt.Errorf("Error: draft id %d does not exist", id)
+	postPath, err := moveToPosts(draft, func(post *goblog.Post) {
+		if post.Published.IsZero() {
+			post.Published = time.Now()
+			post.Updated = post.Published
+		}
+	})
+	if err != nil {
+		return err
 	}
+	golog.Info(`Your draft has been published to: %v.`, postPath)
+	return nil
+}
 
-	draft := sorted[id-1]
+// moveToPosts moves the draft into the posts directory,
+// applying update to its metadata, and returns its new path.
+func moveToPosts(draft goblog.Post, update func(*goblog.Post)) (string, error) {
 	base := filepath.Base(draft.Path)
+	draftPath := path.Join(goblog.Drafts, base)
+	postPath := path.Join(goblog.Posts, base)
 
-	err = os.Rename(
-		path.Join(goblog.Drafts, base),
-		path.Join(goblog.Posts, base),
-	)
+	f, err := os.Open(draftPath)
 	if err != nil {
-		return fmt.Errorf(`Error: failed to move draft to the posts directory: %v`, err)
+		return "", fmt.Errorf("Error: failed to open draft: %v", err)
 	}
+	post, err := goblog.DecodePost(f, draftPath)
+	f.Close()
+	if err != nil {
+		return "", fmt.Errorf("Error: failed to read draft: %v", err)
+	}
+	post.Path = draft.Path
+	update(&post)
 
-	return nil
+	f, err = os.OpenFile(postPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0660)
+	if err != nil {
+		return "", fmt.Errorf("Error: failed to create post: %v", err)
+	}
+	err = goblog.EncodePost(f, post)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(postPath)
+		return "", fmt.Errorf("Error: failed to write post: %v", err)
+	}
+	if err := os.Remove(draftPath); err != nil {
+		return "", fmt.Errorf("Error: failed to remove draft: %v", err)
+	}
+	return postPath, nil
 }
@@ -22,6 +22,7 @@ goblog drafts list    - list drafts
 goblog drafts view    - view the contents of a draft
 goblog drafts delete  - delete a draft
 goblog drafts publish - publishes a draft 
+goblog drafts schedule - publishes a draft at a later time
 `
 
 // Root is the 'drafts' subcommand root handler.
@@ -49,6 +50,8 @@ func Root(ctx context.Context) {
         err = delete(ctx)
 	case "publish":
         err = publish(ctx)
+	case "schedule":
+        err = schedule(ctx)
 	default:
 		golog.Error(`Error: unknown subcommand provided.`)
 		fmt.Printf(usage)
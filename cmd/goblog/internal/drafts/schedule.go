@@ -0,0 +1,115 @@
+package drafts
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ldelossa/goblog"
+	"github.com/ldelossa/goblog/cmd/goblog/internal/initialize"
+	"github.com/ldelossa/goblog/pkg/golog"
+	"github.com/ldelossa/goblog/ui"
+)
+
+var scheduleFS = flag.NewFlagSet("schedule", flag.ExitOnError)
+
+var scheduleFlags = struct {
+	at *string
+}{
+	at: scheduleFS.String("at", "", "the time the post is published at, as 2006-01-02T15:04 in local time, 2006-01-02, or RFC3339"),
+}
+
+// scheduleLayouts are the layouts accepted by the '--at' flag.
+var scheduleLayouts = []string{
+	"2006-01-02T15:04",
+	time.RFC3339,
+	"2006-01-02",
+}
+
+func schedule(ctx context.Context) error {
+	scheduleFS.Usage = func() {
+		fmt.Printf(`
+The schedule subcommand publishes an existing draft at a later time.
+
+The draft is moved to your posts and embedded into the next GoBlog binary you build,
+but is hidden from readers until the time provided to '--at' has passed on the server.
+No rebuild is necessary for the post to appear.
+
+The post's date is set to the scheduled time.
+
+Usage:
+	goblog drafts schedule SLUG|ID --at 2006-01-02T15:04
+
+`)
+	}
+
+	if len(os.Args) < 4 {
+		scheduleFS.Usage()
+		return fmt.Errorf("Error: Not enough arguments provided to 'schedule' subcommand")
+	}
+
+	// first arg is a slug or id
+	ref := os.Args[3]
+
+	// 0: goblog, 1: drafts, 2: schedule, 3: SLUG|ID
+	scheduleFS.Parse(os.Args[4:])
+
+	if *scheduleFlags.at == "" {
+		scheduleFS.Usage()
+		return fmt.Errorf("Error: the '--at' flag is required")
+	}
+	at, err := parseScheduleTime(*scheduleFlags.at)
+	if err != nil {
+		return fmt.Errorf("Error: %v", err)
+	}
+	if !at.After(time.Now()) {
+		return fmt.Errorf("Error: %v is not in the future, use 'goblog drafts publish' instead", at.Format(time.RFC3339))
+	}
+
+	sorted := goblog.LocalDraftsCache
+	if len(sorted) == 0 {
+		golog.Info("There are no drafts to schedule currently.\nUse 'goblog drafts new' to create one.")
+		return nil
+	}
+
+	draft, err := sorted.Lookup(ref)
+	if err != nil {
+		return fmt.Errorf("Error: %v", err)
+	}
+
+	postPath, err := moveToPosts(draft, func(post *goblog.Post) {
+		post.Published = at
+		post.Updated = at
+		post.PublishAt = at
+	})
+	if err != nil {
+		return err
+	}
+
+	golog.Info(`Your draft has been scheduled for %v and written to: %v.`, at.Format(time.RFC1123), postPath)
+
+	build, err := ui.GlobalPrompter.ShouldBuild(ctx)
+	if err != nil {
+		return fmt.Errorf("Error: %v", err)
+	}
+	if build {
+		_, err := initialize.Build(ctx)
+		if err != nil {
+			return fmt.Errorf(`Error: failed to publish new GoBlog binary: %v`, err)
+		}
+	}
+	return nil
+}
+
+// parseScheduleTime parses the '--at' flag, times without
+// a zone are in local time.
+func parseScheduleTime(s string) (time.Time, error) {
+	for _, layout := range scheduleLayouts {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("could not parse time %q, expected a time such as 2026-11-01T09:00", s)
+}
@@ -8,11 +8,9 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
-	"strconv"
 
 	"github.com/ldelossa/goblog"
 	"github.com/ldelossa/goblog/pkg/golog"
-	"gopkg.in/yaml.v3"
 )
 
 var viewFS = flag.NewFlagSet("view", flag.ExitOnError)
@@ -28,7 +26,7 @@ The view subcommand prints draft contents to stdout.
 The '--meta' flag may be used to print both the post content and the metdata data in yaml syntax.
 
 Usage:
-	goblog posts view ID [--meta]
+	goblog posts view SLUG|ID [--meta]
 
 `)
 	}
@@ -45,12 +43,8 @@ Usage:
 		return fmt.Errorf("Error: Not enough arguments to 'view' subcommand\n")
 	}
 
-	// first arg must be id
-	id, err := strconv.Atoi(os.Args[3])
-	if err != nil {
-		viewFS.Usage()
-		return fmt.Errorf("Error: first argument to 'view' subcommand must be an integer id")
-	}
+	// first arg is a slug or id
+	ref := os.Args[3]
 
 	var meta bool
 	for _, arg := range os.Args {
@@ -67,16 +61,11 @@ Usage:
 Use 'goblog drafts new' to create one and 'goblog publish' to build a GoBlog binary with your new posts.`)
 	}
 
-	if id == 0 {
-		return fmt.Errorf("Error: must provide a post id.")
-	}
-
-	if id > len(posts) {
-		return fmt.Errorf("id not found")
+	post, err := posts.Lookup(ref)
+	if err != nil {
+		return fmt.Errorf("Error: %v", err)
 	}
 
-	post := posts[id-1]
-
 	var f fs.File
 	path := filepath.Join(goblog.Src, post.Path)
 	f, err = os.Open(path)
@@ -91,7 +80,7 @@ Use 'goblog drafts new' to create one and 'goblog publish' to build a GoBlog bin
 		}
 	}
 
-	err = yaml.NewDecoder(f).Decode(&post)
+	post, err = goblog.DecodePost(f, post.Path)
 	if err != nil {
 		return fmt.Errorf("error viewing post: " + err.Error())
 	}
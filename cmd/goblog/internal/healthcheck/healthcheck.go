@@ -0,0 +1,87 @@
+package healthcheck
+
+import (
+	"context"
+	"crypto/tls"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+var fs = flag.NewFlagSet("healthcheck", flag.ExitOnError)
+
+var flags = struct {
+	addr    *string
+	path    *string
+	https   *bool
+	timeout *time.Duration
+}{
+	addr:    fs.String("l", "localhost:8080", "a <host:port> or unix:/path string where goblog is listening"),
+	path:    fs.String("path", "/healthz", "the path to probe, such as /healthz or /readyz"),
+	https:   fs.Bool("https", false, "probe over https without verifying the certificate"),
+	timeout: fs.Duration("timeout", 5*time.Second, "how long to wait for a response"),
+}
+
+// Healthcheck probes a running goblog server, returning an
+// error if it does not answer with 200 OK.
+//
+// It requires no goblog home, making it suitable as a container
+// HEALTHCHECK.
+func Healthcheck(ctx context.Context) error {
+	fs.Usage = func() {
+		fmt.Printf(`
+The healthcheck subcommand probes a running goblog server.
+
+It exits 0 if the server answers the probe with 200 OK and 1 otherwise.
+
+Usage:
+	goblog healthcheck [-l ADDR] [--path /healthz] [--https] [--timeout 5s]
+`)
+	}
+
+	// 0: goblog, 1: healthcheck
+	fs.Parse(os.Args[2:])
+
+	transport := &http.Transport{
+		// the server's certificate is for its public name,
+		// not the local address being probed.
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}
+	host := *flags.addr
+	if strings.HasPrefix(host, "unix:") {
+		sock := strings.TrimPrefix(host, "unix:")
+		transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", sock)
+		}
+		host = "localhost"
+	}
+	scheme := "http"
+	if *flags.https {
+		scheme = "https"
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, *flags.timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, scheme+"://"+host+*flags.path, nil)
+	if err != nil {
+		return fmt.Errorf("Error: failed to create probe: %v", err)
+	}
+	client := http.Client{Transport: transport}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("Error: probe failed: %v", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Error: probe answered %v", resp.Status)
+	}
+	fmt.Printf("%v %v\n", req.URL.Path, resp.Status)
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"os/exec"
 	"path"
+	"time"
 
 	"github.com/ldelossa/goblog"
 	"github.com/ldelossa/goblog/git"
@@ -30,6 +31,12 @@ func Build(ctx context.Context) (bool, error) {
 		return false, fmt.Errorf("could not find go command in path: %w", err)
 	}
 
+	// render posts and derive any other generated content
+	// so it is embedded into the binary we are about to build.
+	if err := goblog.Generate(ctx); err != nil {
+		return false, fmt.Errorf("Failed to generate content: %v", err)
+	}
+
 	// we will build our GoBlog binary with the tag name or latest
 	// commit hash found in GoBlog's src directory.
 	//
@@ -40,7 +47,11 @@ func Build(ctx context.Context) (bool, error) {
 	if err != nil {
 		return false, fmt.Errorf("could not GoBlog src tag or commit")
 	}
-	ldFlag := fmt.Sprintf("-X goblog.Version=%s", version)
+	ldFlag := fmt.Sprintf(
+		"-X github.com/ldelossa/goblog.Version=%s -X github.com/ldelossa/goblog.BuildTime=%s",
+		version,
+		time.Now().UTC().Format(time.RFC3339),
+	)
 	goBuild := exec.Cmd{
 		Path:   goPath,
 		Args:   []string{"go", "build", "-o", "../bin/goblog", "-ldflags", ldFlag, "./cmd/goblog"},
@@ -0,0 +1,106 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ldelossa/goblog"
+	"github.com/ldelossa/goblog/pkg/golog"
+)
+
+var formatFS = flag.NewFlagSet("format", flag.ExitOnError)
+
+var formatFlags = struct {
+	to     *string
+	dryRun *bool
+}{
+	to:     formatFS.String("to", "md", "the format to convert to, either md or post"),
+	dryRun: formatFS.Bool("dry-run", false, "list the files which would be converted without converting them"),
+}
+
+func format(ctx context.Context) error {
+	formatFS.Usage = func() {
+		fmt.Printf(`
+The format subcommand converts your local posts and drafts between GoBlog's post formats.
+
+The "md" format is a Markdown file beginning with the post's metadata as YAML front matter
+between '---' lines. This is the format most Markdown tools expect.
+
+The "post" format is a YAML document holding the post's metadata and its Markdown in
+the 'mark_down' key.
+
+Conversion is lossless in both directions. A post's URL changes with its extension,
+GoBlog redirects requests for the previous extension to the converted post.
+
+Usage:
+	goblog migrate format [--to md|post] [--dry-run]
+`)
+	}
+	// 0: goblog, 1: migrate, 2: format
+	formatFS.Parse(os.Args[3:])
+
+	var ext string
+	switch strings.TrimPrefix(*formatFlags.to, ".") {
+	case "md":
+		ext = goblog.MarkdownExt
+	case "post":
+		ext = goblog.PostExt
+	default:
+		formatFS.Usage()
+		return fmt.Errorf("Error: unsupported format: %v", *formatFlags.to)
+	}
+
+	var files []string
+	for _, dir := range []string{goblog.Posts, goblog.Drafts} {
+		err := filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() || !goblog.IsPostFile(p) || filepath.Ext(p) == ext {
+				return nil
+			}
+			files = append(files, p)
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("Error: failed to walk %v: %v", dir, err)
+		}
+	}
+
+	if len(files) == 0 {
+		golog.Info("All posts and drafts are already in the %v format.", *formatFlags.to)
+		return nil
+	}
+
+	var failed int
+	for _, p := range files {
+		rel := strings.TrimPrefix(p, goblog.Src+"/")
+		if *formatFlags.dryRun {
+			golog.Info("%v would be converted", rel)
+			continue
+		}
+		dest, err := goblog.ConvertPost(p, ext)
+		if errors.Is(err, goblog.ErrNoFrontMatter) {
+			golog.Info("%v is not a post, skipping", rel)
+			continue
+		}
+		if err != nil {
+			golog.Error("Error: %v", err)
+			failed++
+			continue
+		}
+		golog.Info("%v -> %v", rel, strings.TrimPrefix(dest, goblog.Src+"/"))
+	}
+	if failed > 0 {
+		return fmt.Errorf("Error: %d of %d posts could not be converted", failed, len(files))
+	}
+	if !*formatFlags.dryRun {
+		golog.Info("Run 'goblog build' to embed the converted posts.")
+	}
+	return nil
+}
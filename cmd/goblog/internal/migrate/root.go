@@ -0,0 +1,43 @@
+package migrate
+
+import (
+	"context"
+	"os"
+
+	"github.com/ldelossa/goblog/pkg/golog"
+)
+
+var usage = `The 'migrate' subcommand is for migrating your local tree between
+GoBlog formats.
+
+Migrated posts are embedded the next time GoBlog is built.
+
+Usage:
+
+goblog migrate format - convert posts and drafts between the .post and .md formats
+
+`
+
+// Root is the 'migrate' subcommand root handler
+func Root(ctx context.Context) {
+	if len(os.Args) < 3 {
+		golog.Info(usage)
+		golog.Error(`Error: The 'migrate' subcommand requires a directive.`)
+		os.Exit(1)
+	}
+
+	var err error
+	switch os.Args[2] {
+	case "--help":
+		golog.Info(usage)
+		os.Exit(0)
+	case "format":
+		err = format(ctx)
+	default:
+		golog.Fatal(`Error: unrecognized subcommand: %s`, os.Args[2])
+	}
+	if err != nil {
+		golog.Error("%v", err)
+		os.Exit(1)
+	}
+}
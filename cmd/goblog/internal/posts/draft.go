@@ -7,7 +7,6 @@ import (
 	"os"
 	"path"
 	"path/filepath"
-	"strconv"
 
 	"github.com/ldelossa/goblog"
 	"github.com/ldelossa/goblog/pkg/golog"
@@ -28,7 +27,7 @@ If the '--local' flag is used it will target only local posts not embedded in a
 Unless the '--local' flag was used, you will need to issue a 'goblog publish' to see the results in a new GoBlog binary.
 
 Usage:
-	goblog posts draft ID
+	goblog posts draft SLUG|ID
 
 `)
 	}
@@ -39,11 +38,8 @@ Usage:
 		return fmt.Errorf(`Error: Not enough arguments to 'draft' subcommand`)
 	}
 
-	id, err := strconv.Atoi(os.Args[3])
-	if err != nil {
-		listFS.Usage()
-		return fmt.Errorf(`Error: 'draft' subcommand requires an integer ID argument`)
-	}
+	// first arg is a slug or id
+	ref := os.Args[3]
 
 	var local bool
 	for _, arg := range os.Args {
@@ -55,9 +51,6 @@ Usage:
 	var posts goblog.DateSortable
 	if local {
 		posts = goblog.LocalPostsCache
-		if err != nil {
-			return fmt.Errorf(`Error: failed to get local posts: %v`, err)
-		}
 	} else {
 		posts = goblog.EmbeddedPostsCache
 	}
@@ -66,15 +59,10 @@ Usage:
 		golog.Info(`There are no posts to view currently.\nUse 'goblog drafts new' to create one and 'goblog build' to build a GoBlog binary with your new posts.`)
 	}
 
-	if id == 0 {
-		return fmt.Errorf(`Error: must provide a post id.`)
-	}
-
-	if id > len(posts) {
-		return fmt.Errorf(`id not found`)
+	post, err := posts.Lookup(ref)
+	if err != nil {
+		return fmt.Errorf("Error: %v", err)
 	}
-
-	post := posts[id-1]
 	base := filepath.Base(post.Path)
 	err = os.Rename(path.Join(goblog.Posts, base), path.Join(goblog.Drafts, base))
 	if err != nil {
@@ -83,7 +71,7 @@ Usage:
 
 	golog.Info(`Post %v successfully moved to drafts.
 
-Use 'goblog build' to build a GoBlog binary with this post removed.`, id)
+Use 'goblog build' to build a GoBlog binary with this post removed.`, post.Slug)
 
 	return nil
 }
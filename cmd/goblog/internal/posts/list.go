@@ -5,7 +5,9 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"strings"
 	"text/tabwriter"
+	"time"
 
 	"github.com/ldelossa/goblog"
 )
@@ -18,11 +20,23 @@ var listFlags = struct {
 func list(ctx context.Context) error {
 	listFS.Usage = func() {
 		fmt.Printf(`
-The 'list' subcommand will list posts in date order.
+The 'list' subcommand will list posts in date order with their slugs and ids.
+
+Other posts subcommands accept either. A slug never changes, an id is the post's position
+in this list and changes as posts are published.
 
 If the '--local' flag is used a list of local posts, ones not emedded into the binary, will be listed.
 
+If the '--tag' flag is used only posts carrying the provided tag will be listed.
+
+Posts scheduled with 'goblog drafts schedule' show the time they are published at in the
+SCHEDULED column until it has passed.
+
 This subcommand takes no arguments.
+
+Usage:
+	goblog posts list [--local] [--tag TAG]
+
 `)
 	}
 
@@ -33,6 +47,20 @@ This subcommand takes no arguments.
 		}
 	}
 
+	var tag string
+	for i, arg := range os.Args {
+		switch {
+		case arg == "--tag" || arg == "-tag":
+			if i+1 >= len(os.Args) {
+				listFS.Usage()
+				return fmt.Errorf("Error: the '--tag' flag requires a tag")
+			}
+			tag = os.Args[i+1]
+		case strings.HasPrefix(arg, "--tag="):
+			tag = strings.TrimPrefix(arg, "--tag=")
+		}
+	}
+
 	var posts goblog.DateSortable
 	var err error
 	if local {
@@ -44,18 +72,27 @@ This subcommand takes no arguments.
 		posts = goblog.EmbeddedPostsCache
 	}
 
+	if tag != "" {
+		posts = posts.Tagged(tag)
+	}
+
 	if len(posts) == 0 {
 		fmt.Println("No posts found.")
 	}
 
+	now := time.Now()
 	tw := tabwriter.NewWriter(os.Stdout, 0, 8, 1, '\t', 0)
 	if local {
-		fmt.Fprintln(tw, "ID\tDATE\tTITLE\tSUMMARY\t(local)")
+		fmt.Fprintln(tw, "ID\tSLUG\tDATE\tSCHEDULED\tTITLE\tSUMMARY\t(local)")
 	} else {
-		fmt.Fprintln(tw, "ID\tDATE\tTITLE\tSUMMARY\t(embedded)")
+		fmt.Fprintln(tw, "ID\tSLUG\tDATE\tSCHEDULED\tTITLE\tSUMMARY\t(embedded)")
 	}
 	for i, post := range posts {
-		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\n", i+1, post.Date.Format("2006-Jan-2"), post.Title, post.Summary)
+		scheduled := "-"
+		if post.Scheduled(now) {
+			scheduled = post.PublishAt.Format("2006-Jan-2 15:04")
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%s\n", i+1, post.Slug, post.Published.Format("2006-Jan-2"), scheduled, post.Title, post.Summary)
 	}
 	err = tw.Flush()
 	if err != nil {
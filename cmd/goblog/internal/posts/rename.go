@@ -0,0 +1,105 @@
+package posts
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/ldelossa/goblog"
+	"github.com/ldelossa/goblog/pkg/golog"
+)
+
+var renameFS = flag.NewFlagSet("rename", flag.ExitOnError)
+
+func rename(ctx context.Context) error {
+	renameFS.Usage = func() {
+		fmt.Printf(`
+The 'rename' subcommand changes the title of a local post.
+
+The post's file name, and so its /posts/ path, is derived from the new title. The previous
+path is recorded in the post's aliases and 'goblog serve' redirects requests for it to the
+new path, so existing links keep working.
+
+The post's slug does not change.
+
+You will need to issue a 'goblog build' to see the results in a new GoBlog binary.
+
+Usage:
+	goblog posts rename SLUG|ID "New Title"
+
+`)
+	}
+
+	// 0: goblog 1: posts 2: rename
+	if len(os.Args) < 5 {
+		renameFS.Usage()
+		return fmt.Errorf(`Error: Not enough arguments to 'rename' subcommand`)
+	}
+	ref, title := os.Args[3], os.Args[4]
+	if title == "" {
+		return fmt.Errorf(`Error: must provide a title`)
+	}
+
+	posts := goblog.LocalPostsCache
+	if len(posts) == 0 {
+		golog.Info(`There are no posts to rename currently.`)
+		return nil
+	}
+
+	post, err := posts.Lookup(ref)
+	if err != nil {
+		return fmt.Errorf("Error: %v", err)
+	}
+
+	oldPath := filepath.Join(goblog.Src, post.Path)
+	f, err := os.Open(oldPath)
+	if err != nil {
+		return fmt.Errorf("Error: failed to open post: %v", err)
+	}
+	renamed, err := goblog.DecodePost(f, oldPath)
+	f.Close()
+	if err != nil {
+		return fmt.Errorf("Error: failed to read post: %v", err)
+	}
+
+	base := goblog.PostFileName(title, path.Ext(post.Path))
+	renamed.Title = title
+	renamed.Slug = post.Slug
+	renamed.Path = path.Join(path.Dir(post.Path), base)
+	if renamed.Path != post.Path {
+		renamed.Aliases = append(renamed.Aliases, "/"+post.Path)
+	}
+	newPath := filepath.Join(goblog.Src, renamed.Path)
+
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	if newPath != oldPath {
+		flags |= os.O_EXCL
+	}
+	f, err = os.OpenFile(newPath, flags, 0660)
+	if err != nil {
+		return fmt.Errorf("Error: failed to create renamed post: %v", err)
+	}
+	err = goblog.EncodePost(f, renamed)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		if newPath != oldPath {
+			os.Remove(newPath)
+		}
+		return fmt.Errorf("Error: failed to write renamed post: %v", err)
+	}
+	if newPath != oldPath {
+		if err := os.Remove(oldPath); err != nil {
+			return fmt.Errorf("Error: failed to remove %v: %v", oldPath, err)
+		}
+	}
+
+	golog.Info(`Post %v renamed to %q at /%v.
+
+Use 'goblog build' to build a GoBlog binary with the renamed post.`, post.Slug, title, renamed.Path)
+	return nil
+}
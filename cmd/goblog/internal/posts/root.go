@@ -19,6 +19,8 @@ Usage:
 goblog posts list  - list published blog posts and their id
 goblog posts view  - view the markdown contents of a post
 goblog posts draft - unpublish a post and move it to draft (assumes --local flag)
+goblog posts search - search the titles, summaries, and contents of posts
+goblog posts rename - change the title of a local post, redirecting its previous path
 
 `
 
@@ -41,6 +43,10 @@ func Root(ctx context.Context) {
         err = draft(ctx)
 	case "view":
 		err = view(ctx)
+	case "search":
+		err = search(ctx)
+	case "rename":
+		err = rename(ctx)
 	default:
 		golog.Fatal(`Error: unrecognized subcommand: %s`, os.Args[2])
 	}
@@ -0,0 +1,98 @@
+package posts
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/ldelossa/goblog"
+)
+
+var searchFS = flag.NewFlagSet("search", flag.ExitOnError)
+
+func search(ctx context.Context) error {
+	searchFS.Usage = func() {
+		fmt.Printf(`
+The 'search' subcommand searches the titles, summaries, and contents of posts.
+
+Results are listed with the most relevant post first along with a snippet of the matching text.
+
+If the '--local' flag is used local posts, ones not embedded into the binary, will be searched.
+
+Usage:
+	goblog posts search QUERY [--local]
+
+`)
+	}
+
+	// 0: goblog 1: posts 2: search
+	if len(os.Args) < 4 {
+		searchFS.Usage()
+		return fmt.Errorf("Error: Not enough arguments to 'search' subcommand")
+	}
+
+	var local bool
+	var terms []string
+	for _, arg := range os.Args[3:] {
+		if arg == "--local" || arg == "-local" {
+			local = true
+			continue
+		}
+		terms = append(terms, arg)
+	}
+	query := strings.Join(terms, " ")
+
+	var idx *goblog.SearchIndex
+	var posts goblog.DateSortable
+	var err error
+	if local {
+		posts = goblog.LocalPostsCache
+		idx, err = goblog.NewSearchIndex(os.DirFS(goblog.Src), posts)
+	} else {
+		posts = goblog.EmbeddedPostsCache
+		idx, err = goblog.EmbeddedSearchIndex()
+	}
+	if err != nil {
+		return fmt.Errorf("Error: failed to build search index: %v", err)
+	}
+
+	match := color.New(color.FgYellow, color.Bold)
+	results := idx.Search(query, goblog.Highlighter{
+		Text:  func(s string) string { return s },
+		Match: func(s string) string { return match.Sprint(s) },
+	})
+	if len(results) == 0 {
+		fmt.Println("No posts found.")
+		return nil
+	}
+
+	// map results back to the ids used by other
+	// 'posts' subcommands.
+	ids := map[string]int{}
+	for i, post := range posts {
+		ids[post.Path] = i + 1
+	}
+
+	// the columns of 'posts list', with the snippet
+	// in place of the summary.
+	now := time.Now()
+	tw := tabwriter.NewWriter(os.Stdout, 0, 8, 1, '\t', 0)
+	fmt.Fprintln(tw, "ID\tSLUG\tDATE\tSCHEDULED\tTITLE\tSNIPPET")
+	for _, res := range results {
+		scheduled := "-"
+		if res.Scheduled(now) {
+			scheduled = res.PublishAt.Format("2006-Jan-2 15:04")
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%s\n", ids[res.Path], res.Slug, res.Published.Format("2006-Jan-2"), scheduled, res.Title, res.Snippet)
+	}
+	err = tw.Flush()
+	if err != nil {
+		return fmt.Errorf("error: " + err.Error())
+	}
+	return nil
+}
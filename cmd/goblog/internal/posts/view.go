@@ -7,11 +7,9 @@ import (
 	"io"
 	"io/fs"
 	"os"
-	"strconv"
 
 	"github.com/ldelossa/goblog"
 	"github.com/ldelossa/goblog/pkg/golog"
-	"gopkg.in/yaml.v3"
 )
 
 var viewFS = flag.NewFlagSet("view", flag.ExitOnError)
@@ -27,7 +25,7 @@ The view subcommand prints post contents to stdout.
 The '--meta' flag may be used to print both the post content and the metdata data in yaml syntax.
 
 Usage:
-	goblog posts view ID [--meta]
+	goblog posts view SLUG|ID [--meta]
 
 `)
 	}
@@ -37,12 +35,8 @@ Usage:
 		return fmt.Errorf("Error: Not enough arguments to 'view' subcommand")
 	}
 
-	// first arg must be id
-	id, err := strconv.Atoi(os.Args[3])
-	if err != nil {
-		listFS.Usage()
-		return fmt.Errorf("Error: first argument to 'view' subcommand must be an integer id")
-	}
+	// first arg is a slug or id
+	ref := os.Args[3]
 
 	var local bool
 	for _, arg := range os.Args {
@@ -61,9 +55,6 @@ Usage:
 	var posts goblog.DateSortable
 	if local {
 		posts = goblog.LocalPostsCache
-		if err != nil {
-			return fmt.Errorf("Error: failed to get local posts: %v", err)
-		}
 	} else {
 		posts = goblog.EmbeddedPostsCache
 	}
@@ -74,16 +65,11 @@ Usage:
 Use 'goblog drafts new' to create one and 'goblog publish' to build a GoBlog binary with your new posts.`)
 	}
 
-	if id == 0 {
-		return fmt.Errorf("Error: must provide a post id.")
-	}
-
-	if id > len(posts) {
-		return fmt.Errorf("id not found")
+	post, err := posts.Lookup(ref)
+	if err != nil {
+		return fmt.Errorf("Error: %v", err)
 	}
 
-	post := posts[id-1]
-
 	var f fs.File
 	if local {
 		f, err = os.Open(post.Path)
@@ -104,7 +90,7 @@ Use 'goblog drafts new' to create one and 'goblog publish' to build a GoBlog bin
 		}
 	}
 
-	err = yaml.NewDecoder(f).Decode(&post)
+	post, err = goblog.DecodePost(f, post.Path)
 	if err != nil {
 		return fmt.Errorf("error viewing post: " + err.Error())
 	}
@@ -0,0 +1,179 @@
+package serve
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ldelossa/goblog"
+)
+
+// the validity of generated development certificates.
+const (
+	devCAValidity   = 10 * 365 * 24 * time.Hour
+	devCertValidity = 365 * 24 * time.Hour
+)
+
+// devTLSDir is where the development CA and certificate
+// are cached.
+func devTLSDir() string {
+	return filepath.Join(goblog.Home, "tls")
+}
+
+// devCertificate returns a certificate for local development
+// valid for localhost and the provided host.
+//
+// The certificate is signed by a self-signed CA generated on
+// first use. Both are cached under goblog.Home so the CA may be
+// trusted by a browser once. The certificate is regenerated if
+// it's expired or does not cover host.
+func devCertificate(host string) (certFile, keyFile string, err error) {
+	dir := devTLSDir()
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", "", fmt.Errorf("failed creating %v: %w", dir, err)
+	}
+
+	ca, caKey, err := loadOrCreateCA(dir)
+	if err != nil {
+		return "", "", err
+	}
+
+	certFile = filepath.Join(dir, "cert.pem")
+	keyFile = filepath.Join(dir, "key.pem")
+	if validCert(certFile, keyFile, ca, host) {
+		return certFile, keyFile, nil
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return "", "", fmt.Errorf("failed generating key: %w", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: serial(),
+		Subject:      pkix.Name{Organization: []string{"GoBlog development"}, CommonName: "localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(devCertValidity),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback},
+	}
+	if host != "" && host != "localhost" {
+		if ip := net.ParseIP(host); ip != nil {
+			tmpl.IPAddresses = append(tmpl.IPAddresses, ip)
+		} else {
+			tmpl.DNSNames = append(tmpl.DNSNames, host)
+		}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca, &key.PublicKey, caKey)
+	if err != nil {
+		return "", "", fmt.Errorf("failed creating certificate: %w", err)
+	}
+	if err := writePEM(certFile, "CERTIFICATE", der); err != nil {
+		return "", "", err
+	}
+	if err := writeKey(keyFile, key); err != nil {
+		return "", "", err
+	}
+	return certFile, keyFile, nil
+}
+
+// loadOrCreateCA loads the development CA cached in dir,
+// creating it if it's missing or expired.
+func loadOrCreateCA(dir string) (*x509.Certificate, *ecdsa.PrivateKey, error) {
+	caFile := filepath.Join(dir, "ca.pem")
+	caKeyFile := filepath.Join(dir, "ca-key.pem")
+
+	if pair, err := tls.LoadX509KeyPair(caFile, caKeyFile); err == nil {
+		ca, err := x509.ParseCertificate(pair.Certificate[0])
+		key, ok := pair.PrivateKey.(*ecdsa.PrivateKey)
+		if err == nil && ok && time.Now().Before(ca.NotAfter) {
+			return ca, key, nil
+		}
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed generating CA key: %w", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          serial(),
+		Subject:               pkix.Name{Organization: []string{"GoBlog development"}, CommonName: "GoBlog development CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(devCAValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLenZero:        true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed creating CA: %w", err)
+	}
+	ca, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := writePEM(caFile, "CERTIFICATE", der); err != nil {
+		return nil, nil, err
+	}
+	if err := writeKey(caKeyFile, key); err != nil {
+		return nil, nil, err
+	}
+	// any certificate signed by a previous CA is
+	// now stale.
+	os.Remove(filepath.Join(dir, "cert.pem"))
+	return ca, key, nil
+}
+
+// validCert reports whether the cached certificate was signed
+// by ca, has not expired, and is valid for host.
+func validCert(certFile, keyFile string, ca *x509.Certificate, host string) bool {
+	pair, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return false
+	}
+	cert, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return false
+	}
+	if time.Now().After(cert.NotAfter) || cert.CheckSignatureFrom(ca) != nil {
+		return false
+	}
+	if host == "" {
+		host = "localhost"
+	}
+	return cert.VerifyHostname(host) == nil
+}
+
+// serial returns a random certificate serial number.
+func serial() *big.Int {
+	n, _ := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	return n
+}
+
+func writeKey(path string, key *ecdsa.PrivateKey) error {
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return fmt.Errorf("failed marshaling key: %w", err)
+	}
+	return writePEM(path, "EC PRIVATE KEY", der)
+}
+
+func writePEM(path, typ string, der []byte) error {
+	b := pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der})
+	if err := os.WriteFile(path, b, 0600); err != nil {
+		return fmt.Errorf("failed writing %v: %w", path, err)
+	}
+	return nil
+}
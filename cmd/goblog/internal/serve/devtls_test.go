@@ -0,0 +1,90 @@
+package serve
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ldelossa/goblog"
+)
+
+func TestDevCertificate(t *testing.T) {
+	defer func(v string) { goblog.Home = v }(goblog.Home)
+	goblog.Home = t.TempDir()
+
+	// verify confirms the certificate is signed by the
+	// cached CA and valid for host.
+	verify := func(certFile, keyFile, host string) []byte {
+		t.Helper()
+		pair, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			t.Fatalf("failed loading certificate: %v", err)
+		}
+		cert, err := x509.ParseCertificate(pair.Certificate[0])
+		if err != nil {
+			t.Fatalf("%v", err)
+		}
+		caPEM, err := os.ReadFile(filepath.Join(devTLSDir(), "ca.pem"))
+		if err != nil {
+			t.Fatalf("CA not found: %v", err)
+		}
+		roots := x509.NewCertPool()
+		roots.AppendCertsFromPEM(caPEM)
+		_, err = cert.Verify(x509.VerifyOptions{DNSName: host, Roots: roots})
+		if err != nil {
+			t.Fatalf("certificate not valid for %v: %v", host, err)
+		}
+		return pair.Certificate[0]
+	}
+	read := func(name string) []byte {
+		t.Helper()
+		b, err := os.ReadFile(filepath.Join(devTLSDir(), name))
+		if err != nil {
+			t.Fatalf("%v", err)
+		}
+		return b
+	}
+
+	certFile, keyFile, err := devCertificate("")
+	if err != nil {
+		t.Fatalf("failed creating certificate: %v", err)
+	}
+	first := verify(certFile, keyFile, "localhost")
+	verify(certFile, keyFile, "127.0.0.1")
+	ca := read("ca.pem")
+
+	// the CA and certificate are reused.
+	certFile, keyFile, err = devCertificate("localhost")
+	if err != nil {
+		t.Fatalf("failed loading certificate: %v", err)
+	}
+	if !bytes.Equal(verify(certFile, keyFile, "localhost"), first) {
+		t.Fatalf("certificate was not reused")
+	}
+
+	// a new host requires a new certificate from the same CA.
+	for _, host := range []string{"blog.test", "192.0.2.1"} {
+		certFile, keyFile, err = devCertificate(host)
+		if err != nil {
+			t.Fatalf("failed creating certificate for %v: %v", host, err)
+		}
+		if bytes.Equal(verify(certFile, keyFile, host), first) {
+			t.Fatalf("certificate was not regenerated for %v", host)
+		}
+		verify(certFile, keyFile, "localhost")
+	}
+	if !bytes.Equal(read("ca.pem"), ca) {
+		t.Fatalf("CA was regenerated")
+	}
+
+	fi, err := os.Stat(filepath.Join(devTLSDir(), "ca-key.pem"))
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if fi.Mode().Perm() != 0600 {
+		t.Fatalf("got CA key mode: %v, want: 0600", fi.Mode().Perm())
+	}
+}
@@ -0,0 +1,115 @@
+package serve
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	unixPrefix = "unix:"
+	fdPrefix   = "fd:"
+	// the first file descriptor passed by systemd
+	// socket activation.
+	listenFDsStart = 3
+	// the permissions of unix sockets goblog creates, a
+	// reverse proxy must share goblog's user or group to
+	// connect.
+	socketMode = 0660
+)
+
+// listen returns a listener for addr along with a function
+// removing any socket file it created.
+//
+// addr is one of:
+//
+//	<host:port>  a TCP address
+//	unix:/path   a Unix domain socket created at /path with
+//	             socketMode permissions, regardless of umask
+//	fd:N         an inherited listening socket, such as one
+//	             passed by systemd socket activation
+func listen(addr string) (net.Listener, func(), error) {
+	switch {
+	case strings.HasPrefix(addr, unixPrefix):
+		return listenUnix(strings.TrimPrefix(addr, unixPrefix))
+	case strings.HasPrefix(addr, fdPrefix):
+		ln, err := listenFD(strings.TrimPrefix(addr, fdPrefix))
+		return ln, func() {}, err
+	}
+	ln, err := net.Listen("tcp", addr)
+	return ln, func() {}, err
+}
+
+func listenUnix(path string) (net.Listener, func(), error) {
+	if path == "" {
+		return nil, nil, fmt.Errorf("no path provided for unix socket")
+	}
+	// a previous goblog may have exited without removing
+	// its socket, only ever remove sockets however.
+	if fi, err := os.Stat(path); err == nil {
+		if fi.Mode()&os.ModeSocket == 0 {
+			return nil, nil, fmt.Errorf("%v exists and is not a socket", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, nil, fmt.Errorf("failed removing stale socket %v: %w", path, err)
+		}
+	}
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, nil, err
+	}
+	// the socket is also unlinked when the listener closes,
+	// this covers exits which never close it.
+	cleanup := func() {
+		os.Remove(path)
+	}
+	// the socket is created with the permissions left by
+	// the umask.
+	if err := os.Chmod(path, socketMode); err != nil {
+		ln.Close()
+		cleanup()
+		return nil, nil, fmt.Errorf("failed setting permissions of %v: %w", path, err)
+	}
+	return ln, cleanup, nil
+}
+
+func listenFD(n string) (net.Listener, error) {
+	fd, err := strconv.Atoi(n)
+	if err != nil || fd < 0 {
+		return nil, fmt.Errorf("could not parse file descriptor: %v", n)
+	}
+	// when launched by systemd ensure the descriptor was
+	// passed to us.
+	if pid := os.Getenv("LISTEN_PID"); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return nil, fmt.Errorf("LISTEN_PID %v does not match goblog's pid", pid)
+	}
+	if fds := os.Getenv("LISTEN_FDS"); fds != "" {
+		count, err := strconv.Atoi(fds)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse LISTEN_FDS: %v", fds)
+		}
+		if fd < listenFDsStart || fd >= listenFDsStart+count {
+			return nil, fmt.Errorf("file descriptor %v was not passed in LISTEN_FDS", fd)
+		}
+	}
+
+	f := os.NewFile(uintptr(fd), fdPrefix+n)
+	if f == nil {
+		return nil, fmt.Errorf("invalid file descriptor: %v", fd)
+	}
+	defer f.Close()
+	ln, err := net.FileListener(f)
+	if err != nil {
+		return nil, fmt.Errorf("file descriptor %v is not a listening socket: %w", fd, err)
+	}
+	return ln, nil
+}
+
+// activated reports whether goblog was passed listening
+// sockets by systemd.
+func activated() bool {
+	pid := os.Getenv("LISTEN_PID")
+	return os.Getenv("LISTEN_FDS") != "" && (pid == "" || pid == strconv.Itoa(os.Getpid()))
+}
@@ -0,0 +1,179 @@
+//go:build !windows
+// +build !windows
+
+package serve
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"testing"
+)
+
+// setenv sets the environment for the duration of the test,
+// an empty value unsets the key.
+func setenv(t *testing.T, env map[string]string) {
+	t.Helper()
+	for k, v := range env {
+		old, ok := os.LookupEnv(k)
+		t.Cleanup(func() {
+			if ok {
+				os.Setenv(k, old)
+			} else {
+				os.Unsetenv(k)
+			}
+		})
+		if v == "" {
+			os.Unsetenv(k)
+		} else {
+			os.Setenv(k, v)
+		}
+	}
+}
+
+func TestListenUnix(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "goblog.sock")
+
+	ln, cleanup, err := listen(unixPrefix + path)
+	if err != nil {
+		t.Fatalf("failed listening: %v", err)
+	}
+	test := func(want os.FileMode) {
+		t.Helper()
+		fi, err := os.Stat(path)
+		if err != nil {
+			t.Fatalf("failed stating socket: %v", err)
+		}
+		if fi.Mode()&os.ModeSocket == 0 {
+			t.Fatalf("%v is not a socket", path)
+		}
+		if got := fi.Mode().Perm(); got != want {
+			t.Fatalf("got: %v, want: %v", got, want)
+		}
+	}
+	test(socketMode)
+
+	// a socket left behind by an earlier goblog is replaced.
+	ln.(*net.UnixListener).SetUnlinkOnClose(false)
+	ln.Close()
+	ln, cleanup, err = listen(unixPrefix + path)
+	if err != nil {
+		t.Fatalf("failed replacing stale socket: %v", err)
+	}
+	test(socketMode)
+	ln.(*net.UnixListener).SetUnlinkOnClose(false)
+	ln.Close()
+	cleanup()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("socket not removed: %v", err)
+	}
+
+	// other files are never removed.
+	file := filepath.Join(dir, "file")
+	if err := os.WriteFile(file, nil, 0600); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if _, _, err := listen(unixPrefix + file); err == nil {
+		t.Fatalf("expected an error listening at a regular file")
+	}
+	if _, err := os.Stat(file); err != nil {
+		t.Fatalf("file removed: %v", err)
+	}
+	if _, _, err := listen(unixPrefix); err == nil {
+		t.Fatalf("expected an error for an empty socket path")
+	}
+}
+
+func TestListenFD(t *testing.T) {
+	tcp, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed listening: %v", err)
+	}
+	defer tcp.Close()
+	// listen takes ownership of the descriptors it's
+	// passed, each case is passed its own duplicate
+	// which no *os.File will close later.
+	dup := func(f *os.File) string {
+		defer f.Close()
+		fd, err := syscall.Dup(int(f.Fd()))
+		if err != nil {
+			t.Fatalf("failed duplicating %v: %v", f.Name(), err)
+		}
+		return strconv.Itoa(fd)
+	}
+	socket := func() string {
+		f, err := tcp.(*net.TCPListener).File()
+		if err != nil {
+			t.Fatalf("failed duplicating listener: %v", err)
+		}
+		return dup(f)
+	}
+	file := func() string {
+		f, err := os.Open(os.DevNull)
+		if err != nil {
+			t.Fatalf("%v", err)
+		}
+		return dup(f)
+	}
+	fixed := func(fd string) func() string {
+		return func() string { return fd }
+	}
+	pid := strconv.Itoa(os.Getpid())
+
+	table := []struct {
+		Name string
+		FD   func() string
+		Env  map[string]string
+		OK   bool
+	}{
+		{"inherited", socket, map[string]string{"LISTEN_FDS": "", "LISTEN_PID": ""}, true},
+		{"not a number", fixed("sock"), nil, false},
+		{"negative", fixed("-1"), nil, false},
+		{"not a socket", file, map[string]string{"LISTEN_FDS": "", "LISTEN_PID": ""}, false},
+		{"other pid", fixed("3"), map[string]string{"LISTEN_FDS": "1", "LISTEN_PID": "1"}, false},
+		{"bad LISTEN_FDS", fixed("3"), map[string]string{"LISTEN_FDS": "many", "LISTEN_PID": pid}, false},
+		{"not passed", fixed("4"), map[string]string{"LISTEN_FDS": "1", "LISTEN_PID": pid}, false},
+		{"below LISTEN_FDS", fixed("2"), map[string]string{"LISTEN_FDS": "1", "LISTEN_PID": pid}, false},
+	}
+	for _, tt := range table {
+		t.Run(tt.Name, func(t *testing.T) {
+			setenv(t, tt.Env)
+			ln, cleanup, err := listen(fdPrefix + tt.FD())
+			if tt.OK != (err == nil) {
+				t.Fatalf("got: %v, want ok: %v", err, tt.OK)
+			}
+			if err != nil {
+				return
+			}
+			defer cleanup()
+			defer ln.Close()
+			if ln.Addr().String() != tcp.Addr().String() {
+				t.Fatalf("got: %v, want: %v", ln.Addr(), tcp.Addr())
+			}
+		})
+	}
+}
+
+func TestActivated(t *testing.T) {
+	pid := strconv.Itoa(os.Getpid())
+	table := []struct {
+		FDs  string
+		PID  string
+		Want bool
+	}{
+		{"", "", false},
+		{"", pid, false},
+		{"1", "", true},
+		{"1", pid, true},
+		{"1", "1", false},
+	}
+	for _, tt := range table {
+		setenv(t, map[string]string{"LISTEN_FDS": tt.FDs, "LISTEN_PID": tt.PID})
+		if got := activated(); got != tt.Want {
+			t.Fatalf("LISTEN_FDS=%q LISTEN_PID=%q: got: %v, want: %v", tt.FDs, tt.PID, got, tt.Want)
+		}
+	}
+}
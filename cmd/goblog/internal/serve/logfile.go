@@ -0,0 +1,129 @@
+package serve
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ldelossa/goblog"
+)
+
+const (
+	// the size an access log may grow to before
+	// it's rotated.
+	logMaxSize = 10 << 20
+	// the number of rotated access logs kept.
+	logBackups = 5
+)
+
+// logDir is where access logs are written.
+func logDir() string {
+	return filepath.Join(goblog.Home, "logs")
+}
+
+// rotatingFile is an io.Writer appending to a file which is
+// rotated once it exceeds maxSize.
+//
+// Rotated files are renamed with a timestamp and only the
+// newest backups are kept.
+type rotatingFile struct {
+	mu      sync.Mutex
+	path    string
+	maxSize int64
+	backups int
+	f       *os.File
+	size    int64
+}
+
+func newRotatingFile(path string, maxSize int64, backups int) (*rotatingFile, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0770); err != nil {
+		return nil, fmt.Errorf("failed creating %v: %w", filepath.Dir(path), err)
+	}
+	r := &rotatingFile{path: path, maxSize: maxSize, backups: backups}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *rotatingFile) open() error {
+	f, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0660)
+	if err != nil {
+		return fmt.Errorf("failed opening %v: %w", r.path, err)
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	r.f, r.size = f, fi.Size()
+	return nil
+}
+
+// Write appends b to the file, rotating it first if b
+// would grow it beyond maxSize.
+//
+// b is written even if rotating fails, the rotation's
+// error is returned.
+func (r *rotatingFile) Write(b []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var rerr error
+	if r.size > 0 && r.size+int64(len(b)) > r.maxSize {
+		rerr = r.rotate()
+	}
+	n, err := r.f.Write(b)
+	r.size += int64(n)
+	if err == nil {
+		err = rerr
+	}
+	return n, err
+}
+
+// rotate renames the current file aside, opens a new
+// one, and removes backups beyond the limit.
+//
+// If the file cannot be renamed it's reopened, it may
+// have been removed, and rotation is retried on the
+// next Write.
+func (r *rotatingFile) rotate() error {
+	ext := filepath.Ext(r.path)
+	base := strings.TrimSuffix(r.path, ext)
+	backup := base + "-" + time.Now().UTC().Format("20060102T150405.000") + ext
+	if err := os.Rename(r.path, backup); err != nil {
+		old := r.f
+		if oerr := r.open(); oerr == nil {
+			old.Close()
+		}
+		return fmt.Errorf("failed rotating %v: %w", r.path, err)
+	}
+	// the renamed file is written to until a new
+	// one is opened.
+	old := r.f
+	if err := r.open(); err != nil {
+		return err
+	}
+	old.Close()
+
+	// timestamps sort lexically, oldest first.
+	matches, err := filepath.Glob(base + "-*" + ext)
+	if err != nil {
+		return err
+	}
+	sort.Strings(matches)
+	for len(matches) > r.backups {
+		os.Remove(matches[0])
+		matches = matches[1:]
+	}
+	return nil
+}
+
+func (r *rotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.f.Close()
+}
@@ -0,0 +1,94 @@
+//go:build !windows
+// +build !windows
+
+package serve
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestRotatingFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "access.log")
+	r, err := newRotatingFile(path, 10, 2)
+	if err != nil {
+		t.Fatalf("failed opening: %v", err)
+	}
+	defer r.Close()
+
+	lines := []string{"0000000000", "1111111111", "2222222222", "3333333333", "4444444444"}
+	for _, line := range lines {
+		if _, err := r.Write([]byte(line)); err != nil {
+			t.Fatalf("failed writing: %v", err)
+		}
+		// backups are named to the millisecond.
+		time.Sleep(2 * time.Millisecond)
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if got, want := string(b), lines[4]; got != want {
+		t.Fatalf("got: %q, want: %q", got, want)
+	}
+
+	// only the newest backups are kept.
+	backups, err := filepath.Glob(filepath.Join(dir, "access-*.log"))
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	sort.Strings(backups)
+	var got []string
+	for _, backup := range backups {
+		b, err := os.ReadFile(backup)
+		if err != nil {
+			t.Fatalf("%v", err)
+		}
+		got = append(got, string(b))
+	}
+	if len(got) != 2 || got[0] != lines[2] || got[1] != lines[3] {
+		t.Fatalf("got backups: %q, want: %q", got, lines[2:4])
+	}
+}
+
+func TestRotatingFileRenameFails(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "access.log")
+	r, err := newRotatingFile(path, 15, 2)
+	if err != nil {
+		t.Fatalf("failed opening: %v", err)
+	}
+	defer r.Close()
+
+	if _, err := r.Write([]byte("0000000000")); err != nil {
+		t.Fatalf("failed writing: %v", err)
+	}
+	// the log removed from under the writer can't be
+	// renamed aside.
+	if err := os.Remove(path); err != nil {
+		t.Fatalf("%v", err)
+	}
+	n, err := r.Write([]byte("1111111111"))
+	if err == nil {
+		t.Fatalf("expected a rotation error")
+	}
+	if n != 10 {
+		t.Fatalf("got: %v bytes written, want: 10", n)
+	}
+	if _, err := r.Write([]byte("22")); err != nil {
+		t.Fatalf("failed writing after a failed rotation: %v", err)
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if got, want := string(b), "111111111122"; got != want {
+		t.Fatalf("got: %q, want: %q", got, want)
+	}
+}
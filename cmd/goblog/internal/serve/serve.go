@@ -2,23 +2,46 @@ package serve
 
 import (
 	"context"
+	"crypto/tls"
 	"flag"
+	"io"
 	"log"
+	"net"
 	"net/http"
+	"net/url"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/ldelossa/goblog"
-	"github.com/rs/cors"
 )
 
 var fs = flag.NewFlagSet("serve", flag.ExitOnError)
 
 var flags = struct {
-	listenAddr *string
+	listenAddr   *string
+	tlsCert      *string
+	tlsKey       *string
+	redirectAddr *string
+	devTLS       *bool
+	accessLog    *string
+	logFile      *bool
+	metricsAddr  *string
+	local        *bool
 }{
-	listenAddr: fs.String("l", "localhost:8080", "a <host:port> string where goblog will listen for http requests"),
+	listenAddr:   fs.String("l", "localhost:8080", "a <host:port>, unix:/path, or fd:N string where goblog will listen for http requests, unix sockets are created with mode 0660"),
+	tlsCert:      fs.String("tls-cert", "", "path to a PEM encoded certificate, serves https when provided with --tls-key"),
+	tlsKey:       fs.String("tls-key", "", "path to the PEM encoded private key of --tls-cert"),
+	redirectAddr: fs.String("redirect", "", "a <host:port> string where plain http requests are redirected to https"),
+	devTLS:       fs.Bool("dev-tls", false, "serve https with a self-signed certificate generated under goblog's home"),
+	accessLog:    fs.String("access-log", goblog.LogCommon, "the access log format, one of common, combined, json, or none"),
+	logFile:      fs.Bool("log-file", false, "write access logs to a rotating file in goblog's home instead of stdout"),
+	metricsAddr:  fs.String("metrics-addr", "", "a <host:port>, unix:/path, or fd:N string where /metrics is served instead of the main listener"),
+	local:        fs.Bool("local", false, "serve posts and the web root from goblog's src directory, reloading browsers on changes"),
 }
 
 // Serve will launch an http server and begin serving blog posts
@@ -27,41 +50,259 @@ func Serve() {
 	// 0: goblog, 1: server
 	fs.Parse(os.Args[2:])
 
-	inter := make(chan os.Signal)
-	signal.Notify(inter, os.Interrupt)
+	inter := make(chan os.Signal, 1)
+	signal.Notify(inter, os.Interrupt, syscall.SIGTERM)
+
+	// when socket activated by systemd listen on the
+	// passed socket unless told otherwise.
+	listenAddr := *flags.listenAddr
+	explicit := false
+	fs.Visit(func(f *flag.Flag) {
+		explicit = explicit || f.Name == "l"
+	})
+	if !explicit && activated() {
+		listenAddr = fdPrefix + strconv.Itoa(listenFDsStart)
+	}
+
+	// flags take precedence over the embedded config.
+	tlsConf := goblog.Conf.TLS
+	if *flags.tlsCert != "" || *flags.tlsKey != "" {
+		tlsConf.CertFile, tlsConf.KeyFile = *flags.tlsCert, *flags.tlsKey
+	}
+	if *flags.redirectAddr != "" {
+		tlsConf.RedirectAddr = *flags.redirectAddr
+	}
+	if *flags.devTLS {
+		host, _, _ := net.SplitHostPort(listenAddr)
+		var err error
+		tlsConf.CertFile, tlsConf.KeyFile, err = devCertificate(host)
+		if err != nil {
+			log.Printf("Failed creating development certificate: %v\n", err)
+			os.Exit(1)
+		}
+		log.Printf("Serving development certificate, trust %v to avoid browser warnings\n", filepath.Join(devTLSDir(), "ca.pem"))
+	}
+	if (tlsConf.CertFile == "") != (tlsConf.KeyFile == "") {
+		log.Printf("Both a TLS certificate and key must be provided\n")
+		os.Exit(1)
+	}
+	useTLS := tlsConf.CertFile != ""
+
+	// canceling stops watching the local tree, disconnecting
+	// any browsers waiting on reload events.
+	wctx, stopWatch := context.WithCancel(context.Background())
+	defer stopWatch()
+	if *flags.local {
+		if err := goblog.ServeLocal(context.Background()); err != nil {
+			log.Printf("Failed serving local tree: %v\n", err)
+			os.Exit(1)
+		}
+		go goblog.WatchLocal(wctx, 500*time.Millisecond, func(err error) {
+			log.Printf("Failed reloading local tree: %v\n", err)
+		})
+		log.Printf("Serving local tree @ %v\n", goblog.Src)
+	} else {
+		// embedded content never changes, hash it once
+		// up front to serve entity tags.
+		if err := goblog.HashEmbeddedContent(); err != nil {
+			log.Printf("Failed hashing embedded content: %v\n", err)
+			os.Exit(1)
+		}
+	}
 
 	var mux http.ServeMux
-	mux.Handle("/posts/", goblog.PostsHandler())
-	mux.Handle("/summaries", goblog.SummaryHandler())
-	mux.Handle("/", goblog.WebHandler(goblog.Conf.AppPaths))
+	if *flags.local {
+		mux.Handle("/_goblog/reload", goblog.ReloadHandler())
+	}
+	mux.Handle("/posts/", goblog.InstrumentHandler("posts", goblog.PostsHandler()))
+	mux.Handle("/summaries", goblog.InstrumentHandler("summary", goblog.SummaryHandler()))
+	mux.Handle("/search", goblog.InstrumentHandler("search", goblog.SearchHandler()))
+	mux.Handle("/tags", goblog.InstrumentHandler("tags", goblog.TagsHandler()))
+	mux.Handle("/tags/", goblog.InstrumentHandler("tags", goblog.TagsHandler()))
+	mux.Handle("/feed.rss", goblog.InstrumentHandler("feed", goblog.RSSHandler()))
+	mux.Handle("/feed.atom", goblog.InstrumentHandler("feed", goblog.AtomHandler()))
+	mux.Handle("/feed.json", goblog.InstrumentHandler("feed", goblog.JSONFeedHandler()))
+	mux.Handle("/sitemap.xml", goblog.InstrumentHandler("sitemap", goblog.SitemapHandler(goblog.Conf.AppPaths)))
+	mux.Handle("/robots.txt", goblog.InstrumentHandler("sitemap", goblog.RobotsHandler(goblog.Conf.AppPaths)))
+	mux.Handle("/", goblog.InstrumentHandler("web", goblog.WebHandler(goblog.Conf.AppPaths)))
+	mux.Handle("/healthz", goblog.HealthzHandler())
+	mux.Handle("/readyz", goblog.ReadyzHandler())
+	mux.Handle("/version", goblog.VersionHandler())
+
+	// metrics are served on the main listener unless an
+	// admin listener is requested.
+	var admin *http.Server
+	if *flags.metricsAddr != "" {
+		var adminMux http.ServeMux
+		adminMux.Handle("/metrics", goblog.MetricsHandler())
+		admin = &http.Server{
+			Addr:    *flags.metricsAddr,
+			Handler: &adminMux,
+		}
+	} else {
+		mux.Handle("/metrics", goblog.MetricsHandler())
+	}
+
+	handler := goblog.Conf.CORS.Handler(goblog.CompressHandler(goblog.RedirectHandler(&mux)))
+	if *flags.accessLog != "none" {
+		var out io.Writer = os.Stdout
+		if *flags.logFile {
+			f, err := newRotatingFile(filepath.Join(logDir(), "access.log"), logMaxSize, logBackups)
+			if err != nil {
+				log.Printf("Failed opening access log: %v\n", err)
+				os.Exit(1)
+			}
+			out = f
+		}
+		var err error
+		handler, err = goblog.AccessLogHandler(out, *flags.accessLog, handler)
+		if err != nil {
+			log.Printf("%v\n", err)
+			os.Exit(1)
+		}
+	}
 
 	server := &http.Server{
 		Addr:    *flags.listenAddr,
-		Handler: cors.Default().Handler(&mux),
+		Handler: handler,
+	}
+	// HTTP/2 is negotiated over TLS by the http.Server
+	// as long as TLSNextProto is left nil.
+	if useTLS {
+		server.TLSConfig = &tls.Config{
+			MinVersion: tls.VersionTLS12,
+			NextProtos: []string{"h2", "http/1.1"},
+		}
+	}
+
+	var redirect *http.Server
+	if useTLS && tlsConf.RedirectAddr != "" {
+		redirect = &http.Server{
+			Addr:    tlsConf.RedirectAddr,
+			Handler: redirectHandler(listenAddr),
+		}
+	}
+
+	ln, cleanup, err := listen(listenAddr)
+	if err != nil {
+		log.Printf("Failed listening @ %v: %v\n", listenAddr, err)
+		os.Exit(1)
+	}
+	var redirectLn net.Listener
+	redirectCleanup := func() {}
+	if redirect != nil {
+		redirectLn, redirectCleanup, err = listen(redirect.Addr)
+		if err != nil {
+			cleanup()
+			log.Printf("Failed listening @ %v: %v\n", redirect.Addr, err)
+			os.Exit(1)
+		}
+	}
+	var adminLn net.Listener
+	adminCleanup := func() {}
+	if admin != nil {
+		adminLn, adminCleanup, err = listen(admin.Addr)
+		if err != nil {
+			cleanup()
+			redirectCleanup()
+			log.Printf("Failed listening @ %v: %v\n", admin.Addr, err)
+			os.Exit(1)
+		}
+	}
+	// removes any socket files before exiting.
+	exit := func(code int) {
+		cleanup()
+		redirectCleanup()
+		adminCleanup()
+		os.Exit(code)
 	}
 
 	ctx, cancel := context.WithCancel(context.Background())
 	var httpErr error
 	go func() {
-		log.Printf("Launching goblog @ %v\n", *flags.listenAddr)
-		err := server.ListenAndServe()
+		var err error
+		if useTLS {
+			log.Printf("Launching goblog @ https://%v\n", listenAddr)
+			err = server.ServeTLS(ln, tlsConf.CertFile, tlsConf.KeyFile)
+		} else {
+			log.Printf("Launching goblog @ %v\n", listenAddr)
+			err = server.Serve(ln)
+		}
 		if err != nil {
 			httpErr = err
 			cancel()
 		}
 	}()
+	if redirect != nil {
+		go func() {
+			log.Printf("Redirecting http requests @ %v to https\n", redirect.Addr)
+			err := redirect.Serve(redirectLn)
+			if err != nil && err != http.ErrServerClosed {
+				log.Printf("Received http redirect error: %v\n", err)
+			}
+		}()
+	}
+
+	if admin != nil {
+		go func() {
+			log.Printf("Serving metrics @ %v\n", admin.Addr)
+			err := admin.Serve(adminLn)
+			if err != nil && err != http.ErrServerClosed {
+				log.Printf("Received http metrics error: %v\n", err)
+			}
+		}()
+	}
+
+	// the listeners are bound, requests will queue until
+	// served.
+	goblog.SetReady(true)
 
 	select {
 	case <-inter:
 		log.Printf("Received interupt. Gracefully shutting down server.\n")
+		goblog.SetReady(false)
+		stopWatch()
 		tctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
+		if redirect != nil {
+			redirect.Shutdown(tctx)
+		}
+		if admin != nil {
+			admin.Shutdown(tctx)
+		}
 		server.Shutdown(tctx)
 	case <-ctx.Done():
 		if httpErr != http.ErrServerClosed {
 			log.Printf("Received http error: %v\n", httpErr)
-			os.Exit(1)
+			exit(1)
+		}
+	}
+	exit(0)
+}
+
+// redirectHandler redirects requests to the same host and path
+// over https, on the port of the TLS listener's address.
+//
+// The port of a unix: or fd: listener is unknown, the default
+// port is redirected to.
+func redirectHandler(tlsAddr string) http.HandlerFunc {
+	var port string
+	if !strings.HasPrefix(tlsAddr, unixPrefix) && !strings.HasPrefix(tlsAddr, fdPrefix) {
+		_, port, _ = net.SplitHostPort(tlsAddr)
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(r.Host); err == nil {
+			host = h
+		}
+		switch {
+		case port != "" && port != "443":
+			host = net.JoinHostPort(host, port)
+		case strings.Contains(host, ":"):
+			// an IPv6 address.
+			host = "[" + host + "]"
 		}
+		u := url.URL{Scheme: "https", Host: host, Path: r.URL.Path, RawQuery: r.URL.RawQuery}
+		http.Redirect(w, r, u.String(), http.StatusMovedPermanently)
 	}
-	os.Exit(0)
 }
@@ -0,0 +1,38 @@
+package serve
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRedirectHandler(t *testing.T) {
+	table := []struct {
+		TLSAddr string
+		Target  string
+		Host    string
+		Want    string
+	}{
+		{":443", "/posts/hello.md?format=html", "blog.example.com", "https://blog.example.com/posts/hello.md?format=html"},
+		{"0.0.0.0:443", "/", "blog.example.com:80", "https://blog.example.com/"},
+		{":8443", "/", "localhost", "https://localhost:8443/"},
+		{"localhost:8443", "/about", "localhost:8080", "https://localhost:8443/about"},
+		{":443", "/", "[::1]:80", "https://[::1]/"},
+		{":8443", "/", "[::1]:8080", "https://[::1]:8443/"},
+		// without a port the default is assumed.
+		{"fd:3", "/", "blog.example.com:8080", "https://blog.example.com/"},
+		{"unix:/run/goblog.sock", "/", "blog.example.com", "https://blog.example.com/"},
+	}
+	for _, tt := range table {
+		req := httptest.NewRequest(http.MethodGet, tt.Target, nil)
+		req.Host = tt.Host
+		rec := httptest.NewRecorder()
+		redirectHandler(tt.TLSAddr)(rec, req)
+		if rec.Code != http.StatusMovedPermanently {
+			t.Fatalf("%v: got: %v, want: %v", tt.TLSAddr, rec.Code, http.StatusMovedPermanently)
+		}
+		if got := rec.Header().Get("Location"); got != tt.Want {
+			t.Fatalf("%v, Host %v: got: %v, want: %v", tt.TLSAddr, tt.Host, got, tt.Want)
+		}
+	}
+}
@@ -12,7 +12,9 @@ import (
 	"github.com/ldelossa/goblog/cmd/goblog/internal/config"
 	"github.com/ldelossa/goblog/cmd/goblog/internal/diff"
 	"github.com/ldelossa/goblog/cmd/goblog/internal/drafts"
+	"github.com/ldelossa/goblog/cmd/goblog/internal/healthcheck"
 	"github.com/ldelossa/goblog/cmd/goblog/internal/initialize"
+	"github.com/ldelossa/goblog/cmd/goblog/internal/migrate"
 	"github.com/ldelossa/goblog/cmd/goblog/internal/posts"
 	"github.com/ldelossa/goblog/cmd/goblog/internal/serve"
 	"github.com/ldelossa/goblog/cmd/goblog/internal/upgrade"
@@ -28,12 +30,15 @@ The command is split into subcommands, each containing their own help content.
 goblog init    - create a new goblog environment
 goblog config  - update configuration details
 goblog serve   - serve your blog posts, assests, and web root over http
+                 use "goblog serve --local" to serve and live reload $HOME/src
 goblog posts   - list, view, and remove published posts
 goblog drafts  - list, create, publish, and delete draft blog posts
 goblog build   - build a new goblog binary with the latest posts and web root
 goblog diff    - diff the contents of your local and embedded tree
 goblog preview - preview your blog by running the code in $HOME/src directly
 goblog upgrade - upgrade goblog to the newest or specific version
+goblog migrate - migrate your local tree between goblog formats
+goblog healthcheck - probe a running goblog server
 `
 
 func main() {
@@ -97,6 +102,15 @@ func main() {
 			golog.Error("%v", err)
 			os.Exit(1)
 		}
+	case "migrate":
+		initialize.Initialize(ctx)
+		migrate.Root(ctx)
+	case "healthcheck":
+		err := healthcheck.Healthcheck(ctx)
+		if err != nil {
+			golog.Error("%v", err)
+			os.Exit(1)
+		}
 	default:
 		fmt.Println(usage)
 		fmt.Printf("Error: unrecognized subcommand: %s\n", os.Args[1])
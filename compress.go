@@ -0,0 +1,239 @@
+package goblog
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/fs"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+)
+
+// encoding is a content encoding GoBlog may serve.
+type encoding struct {
+	// the Content-Encoding token.
+	name string
+	// the extension of precompressed siblings.
+	ext string
+	// returns a writer compressing to w.
+	writer func(w io.Writer) io.WriteCloser
+}
+
+// encodings are listed in order of preference.
+var encodings = []encoding{
+	{
+		name: "br",
+		ext:  ".br",
+		writer: func(w io.Writer) io.WriteCloser {
+			return brotli.NewWriterLevel(w, brotli.BestCompression)
+		},
+	},
+	{
+		name: "gzip",
+		ext:  ".gz",
+		writer: func(w io.Writer) io.WriteCloser {
+			gz, _ := gzip.NewWriterLevel(w, gzip.BestCompression)
+			return gz
+		},
+	},
+}
+
+// incompressible are extensions of formats which
+// are already compressed.
+var incompressible = map[string]bool{
+	".png": true, ".jpg": true, ".jpeg": true, ".gif": true, ".webp": true,
+	".avif": true, ".ico": true, ".woff": true, ".woff2": true, ".zip": true,
+	".gz": true, ".br": true, ".mp3": true, ".mp4": true, ".webm": true,
+	".ogg": true, ".pdf": true,
+}
+
+// compressible reports whether a file with the
+// provided name is worth compressing.
+func compressible(name string) bool {
+	return !incompressible[strings.ToLower(path.Ext(name))]
+}
+
+// compressedPath returns the path in GeneratedFS of the
+// precompressed sibling of the embedded file at p.
+//
+// Siblings are kept apart from the generated content, a post
+// asset could otherwise share a sibling with a rendered post.
+func compressedPath(p string, enc encoding) string {
+	return path.Join(compressedDir, p) + enc.ext
+}
+
+// compress returns b compressed with the provided
+// encoding.
+func compress(b []byte, enc encoding) ([]byte, error) {
+	var buf bytes.Buffer
+	w := enc.writer(&buf)
+	if _, err := w.Write(b); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// acceptsEncoding reports whether the request's Accept-Encoding
+// header accepts the named encoding.
+func acceptsEncoding(r *http.Request, name string) bool {
+	for _, part := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		fields := strings.Split(part, ";")
+		if strings.TrimSpace(fields[0]) != name {
+			continue
+		}
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				q, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64)
+				if err != nil || q == 0 {
+					return false
+				}
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// serveEmbedded serves the embedded file at p whose contents
+// are b.
+//
+// If the binary embeds a precompressed sibling of the file in
+// an encoding the client accepts the sibling is served instead.
+func serveEmbedded(w http.ResponseWriter, r *http.Request, p string, b []byte) {
+	addVary(w.Header(), "Accept-Encoding")
+	for _, enc := range encodings {
+		if !acceptsEncoding(r, enc.name) {
+			continue
+		}
+		cp := compressedPath(p, enc)
+		compressed, err := fs.ReadFile(generatedFS(), cp)
+		if err != nil {
+			continue
+		}
+		// the compressed bytes can't be sniffed, determine
+		// the type from the original content.
+		if w.Header().Get("Content-Type") == "" {
+			w.Header().Set("Content-Type", contentType(p, b))
+		}
+		w.Header().Set("Content-Encoding", enc.name)
+		serveContent(w, r, p, cp, compressed)
+		return
+	}
+	serveContent(w, r, p, p, b)
+}
+
+// addVary adds token to the Vary header of h unless
+// it is already present.
+func addVary(h http.Header, token string) {
+	for _, v := range h.Values("Vary") {
+		for _, t := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return
+			}
+		}
+	}
+	h.Add("Vary", token)
+}
+
+// CompressHandler compresses responses on the fly for clients
+// accepting a supported encoding.
+//
+// Responses which are already encoded, partial, or of a format
+// that is already compressed are passed through untouched.
+//
+// This is a fallback for content GoBlog could not precompress
+// at build time.
+func CompressHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		addVary(w.Header(), "Accept-Encoding")
+		for _, enc := range encodings {
+			if acceptsEncoding(r, enc.name) {
+				cw := &compressWriter{ResponseWriter: w, enc: enc}
+				defer cw.Close()
+				h.ServeHTTP(cw, r)
+				return
+			}
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// compressWriter is a http.ResponseWriter which decides whether
+// to compress the response once its headers are written.
+type compressWriter struct {
+	http.ResponseWriter
+	enc         encoding
+	w           io.WriteCloser
+	wroteHeader bool
+}
+
+func (c *compressWriter) WriteHeader(code int) {
+	if c.wroteHeader {
+		return
+	}
+	c.wroteHeader = true
+
+	h := c.Header()
+	ctype := h.Get("Content-Type")
+	skip := code < 200 || code == http.StatusNoContent || code == http.StatusNotModified ||
+		code == http.StatusPartialContent ||
+		h.Get("Content-Encoding") != "" ||
+		h.Get("Content-Range") != "" ||
+		ctype == "" ||
+		strings.HasPrefix(ctype, "text/event-stream") ||
+		strings.HasPrefix(ctype, "image/") ||
+		strings.HasPrefix(ctype, "video/") ||
+		strings.HasPrefix(ctype, "audio/")
+	if !skip {
+		h.Set("Content-Encoding", c.enc.name)
+		h.Del("Content-Length")
+		// the encoded response is no longer byte for byte
+		// the representation a strong ETag identifies.
+		if tag := h.Get("ETag"); tag != "" && !strings.HasPrefix(tag, "W/") {
+			h.Set("ETag", "W/"+tag)
+		}
+		c.w = c.enc.writer(c.ResponseWriter)
+	}
+	c.ResponseWriter.WriteHeader(code)
+}
+
+func (c *compressWriter) Write(b []byte) (int, error) {
+	if !c.wroteHeader {
+		if c.Header().Get("Content-Type") == "" {
+			c.Header().Set("Content-Type", http.DetectContentType(b))
+		}
+		c.WriteHeader(http.StatusOK)
+	}
+	if c.w != nil {
+		return c.w.Write(b)
+	}
+	return c.ResponseWriter.Write(b)
+}
+
+// Flush implements http.Flusher, flushing any buffered
+// compressed data to the client.
+func (c *compressWriter) Flush() {
+	if f, ok := c.w.(interface{ Flush() error }); ok {
+		f.Flush()
+	}
+	if f, ok := c.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Close flushes any compressed data to the underlying
+// http.ResponseWriter.
+func (c *compressWriter) Close() error {
+	if c.w != nil {
+		return c.w.Close()
+	}
+	return nil
+}
@@ -6,6 +6,12 @@ package goblog
 // This supports identifying upgrades.
 var Version string = "dev"
 
+// BuildTime is the RFC3339 time the goblog binary was built.
+//
+// This is set when GoBlog builds itself and is empty
+// otherwise.
+var BuildTime string
+
 type Config struct {
 	// The paths your front-end web applications serves.
 	// When GoBlog encounters these paths it will serve
@@ -13,4 +19,53 @@ type Config struct {
 	//
 	// This is how deep linking is supported.
 	AppPaths []string
+	// The title of your blog.
+	//
+	// Used as the site-level title of the generated feeds.
+	Title string
+	// A short description of your blog.
+	Description string
+	// The public URL your blog is served from, such as
+	// "https://blog.example.com".
+	//
+	// Feeds use this to create absolute links to your posts.
+	BaseURL string
+	// The name of the blog's author.
+	Author string
+	// An optional contact email for the blog's author.
+	Email string
+	// The Cache-Control headers served with web root
+	// and post assets.
+	Cache CachePolicy
+	// When true 'goblog build' fingerprints the web root,
+	// embedding a copy of each asset with a content hash in
+	// its name and rewriting references to it in index.html
+	// and CSS files.
+	//
+	// Fingerprinted assets may be cached indefinitely.
+	Fingerprint bool
+	// TLS configures 'goblog serve' to serve HTTPS.
+	TLS TLSConfig
+	// The cross-origin requests 'goblog serve' allows.
+	CORS CORSPolicy
+	// Paths 'goblog serve' redirects with a 301, keyed by the
+	// requested path, such as "/about" to "/posts/about.post".
+	// A target may also be an absolute URL.
+	//
+	// Moved posts record their previous paths as Aliases
+	// instead.
+	Redirects map[string]string
+}
+
+// TLSConfig holds the certificate GoBlog serves HTTPS with.
+//
+// Flags passed to 'goblog serve' take precedence.
+type TLSConfig struct {
+	// Paths to a PEM encoded certificate chain and its
+	// private key. HTTPS is served when both are set.
+	CertFile string
+	KeyFile  string
+	// An optional <host:port> where plain HTTP requests
+	// are redirected to HTTPS.
+	RedirectAddr string
 }
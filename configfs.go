@@ -2,6 +2,9 @@ package goblog
 
 import (
 	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
 
 	"gopkg.in/yaml.v3"
 )
@@ -25,3 +28,23 @@ func init() {
 
 //go:embed config
 var ConfigFS embed.FS
+
+// LocalConfig decodes the local config file found in
+// the Configs directory.
+//
+// The local config may differ from the embedded Conf
+// until a new GoBlog binary is built.
+func LocalConfig() (Config, error) {
+	var conf Config
+	f, err := os.Open(filepath.Join(Configs, "config.yaml"))
+	if err != nil {
+		return conf, fmt.Errorf("failed opening local config: %w", err)
+	}
+	defer f.Close()
+
+	err = yaml.NewDecoder(f).Decode(&conf)
+	if err != nil {
+		return conf, fmt.Errorf("failed decoding local config: %w", err)
+	}
+	return conf, nil
+}
@@ -0,0 +1,88 @@
+package goblog
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/rs/cors"
+)
+
+// CORSPolicy configures which cross-origin requests
+// 'goblog serve' allows.
+//
+// The zero value allows GET, HEAD, and POST requests from
+// every origin.
+type CORSPolicy struct {
+	// Origins allowed to make cross-origin requests, such as
+	// "https://blog.example.com". An origin may contain a
+	// single "*" wildcard, as in "https://*.example.com".
+	//
+	// Empty allows every origin.
+	AllowedOrigins []string
+	// Methods allowed in cross-origin requests.
+	//
+	// Empty allows GET, HEAD, and POST.
+	AllowedMethods []string
+	// Non-simple headers allowed in cross-origin requests.
+	AllowedHeaders []string
+	// Response headers exposed to cross-origin requests.
+	//
+	// Empty exposes the paging headers of SummaryHandler.
+	ExposedHeaders []string
+	// Whether cross-origin requests may include credentials
+	// such as cookies. Requires AllowedOrigins, it's ignored
+	// when every origin is allowed.
+	AllowCredentials bool
+	// The number of seconds a preflight response may be
+	// cached, 0 leaves this to the client.
+	MaxAge int
+	// Path prefixes the policy applies to, such as "/summaries"
+	// and "/posts/". Other paths allow every origin.
+	//
+	// Empty applies the policy to every path.
+	Paths []string
+}
+
+// Handler returns h wrapped in the policy.
+func (p CORSPolicy) Handler(h http.Handler) http.Handler {
+	exposed := p.ExposedHeaders
+	if len(exposed) == 0 {
+		exposed = []string{"X-Total-Count", "Link"}
+	}
+	policy := cors.New(cors.Options{
+		AllowedOrigins:   p.AllowedOrigins,
+		AllowedMethods:   p.AllowedMethods,
+		AllowedHeaders:   p.AllowedHeaders,
+		ExposedHeaders:   exposed,
+		AllowCredentials: p.AllowCredentials && !anyOrigin(p.AllowedOrigins),
+		MaxAge:           p.MaxAge,
+	}).Handler(h)
+	if len(p.Paths) == 0 {
+		return policy
+	}
+
+	open := cors.New(cors.Options{ExposedHeaders: exposed}).Handler(h)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, prefix := range p.Paths {
+			if strings.HasPrefix(r.URL.Path, prefix) {
+				policy.ServeHTTP(w, r)
+				return
+			}
+		}
+		open.ServeHTTP(w, r)
+	})
+}
+
+// anyOrigin reports whether origins allows every origin,
+// credentials are never shared with every origin.
+func anyOrigin(origins []string) bool {
+	if len(origins) == 0 {
+		return true
+	}
+	for _, o := range origins {
+		if o == "*" {
+			return true
+		}
+	}
+	return false
+}
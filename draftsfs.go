@@ -3,13 +3,12 @@ package goblog
 import (
 	"context"
 	"embed"
+	"errors"
 	"io/fs"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
-
-	"gopkg.in/yaml.v3"
 )
 
 //go:embed drafts/*
@@ -42,16 +41,20 @@ func NewEmbeddedDraftsCache() (DateSortable, error) {
 		if d.IsDir() {
 			return nil
 		}
-		if filepath.Ext(d.Name()) != ".post" {
+		if !IsPostFile(d.Name()) {
 			return nil
 		}
 		f, err := DraftsFS.Open(p)
 		if err != nil {
 			return err
 		}
+		defer f.Close()
 
-		var post Post
-		err = yaml.NewDecoder(f).Decode(&post)
+		post, err := DecodePost(f, p)
+		// Markdown files without front matter are assets.
+		if errors.Is(err, ErrNoFrontMatter) {
+			return nil
+		}
 		if err != nil {
 			return err
 		}
@@ -62,13 +65,9 @@ func NewEmbeddedDraftsCache() (DateSortable, error) {
 			return nil
 		}
 
-		sorted = append(sorted, Post{
-			Path:    p,
-			Title:   post.Title,
-			Summary: post.Summary,
-			Date:    post.Date,
-			Hero:    post.Hero,
-		})
+		post.Path = p
+		setDefaults(&post)
+		sorted = append(sorted, summary(post))
 		return nil
 	})
 	if err != nil {
@@ -92,14 +91,20 @@ func NewLocalDraftsCache(ctx context.Context) (DateSortable, error) {
 			return nil
 		}
 
+		if !IsPostFile(path) {
+			return nil
+		}
 		f, err := os.Open(path)
 		if err != nil {
 			return err
 		}
+		defer f.Close()
 
-		var post Post
-
-		err = yaml.NewDecoder(f).Decode(&post)
+		post, err := DecodePost(f, path)
+		// Markdown files without front matter are assets.
+		if errors.Is(err, ErrNoFrontMatter) {
+			return nil
+		}
 		if err != nil {
 			return err
 		}
@@ -108,6 +113,7 @@ func NewLocalDraftsCache(ctx context.Context) (DateSortable, error) {
 		}
 
 		post.Path = strings.TrimPrefix(path, Src+"/")
+		setDefaults(&post)
 		sorted = append(sorted, post)
 		return nil
 	})
@@ -0,0 +1,137 @@
+package goblog
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"io/fs"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// etags caches the entity tags of served content keyed by
+// a name identifying the representation, such as an embedded
+// path or a post path and format.
+//
+// Content is immutable for the life of the binary so a
+// representation only needs to be hashed once. Content which
+// changes as scheduled posts are revealed is not cached.
+var etags = struct {
+	sync.RWMutex
+	m map[string]string
+}{m: map[string]string{}}
+
+// startTime is reported as the modification time of served
+// content when the binary was not built with a BuildTime.
+var startTime = time.Now()
+
+// HashEmbeddedContent computes the entity tags of every file
+// embedded in PostsFS and WebFS.
+//
+// Calling this when a server starts avoids hashing files
+// while serving their first request.
+func HashEmbeddedContent() error {
+	for _, fsys := range []embed.FS{PostsFS, WebFS} {
+		err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				return nil
+			}
+			b, err := fs.ReadFile(fsys, p)
+			if err != nil {
+				return err
+			}
+			etag(p, b)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// etag returns the entity tag cached for key, computing
+// and caching it from b on a miss.
+//
+// An empty key computes the entity tag without caching it,
+// useful for content which varies per request.
+func etag(key string, b []byte) string {
+	if key == "" {
+		return hashETag(b)
+	}
+
+	etags.RLock()
+	tag, ok := etags.m[key]
+	etags.RUnlock()
+	if ok {
+		return tag
+	}
+
+	tag = hashETag(b)
+
+	etags.Lock()
+	etags.m[key] = tag
+	etags.Unlock()
+	return tag
+}
+
+// hashETag returns a strong entity tag derived
+// from the contents of b.
+func hashETag(b []byte) string {
+	sum := sha256.Sum256(b)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// ModTime is the modification time reported for served content.
+//
+// This is the time the binary was built or, if unknown, the
+// time the process started.
+func ModTime() time.Time {
+	if t, err := time.Parse(time.RFC3339, BuildTime); err == nil {
+		return t
+	}
+	return startTime
+}
+
+// publishedModTime is the modification time reported for
+// content listing the served posts.
+//
+// Revealing a scheduled post changes such content without a
+// new binary, so this is the later of ModTime and the time the
+// most recently revealed post was published at.
+func publishedModTime() time.Time {
+	mod := ModTime()
+	now := time.Now()
+	for _, post := range postsCache() {
+		if !post.Scheduled(now) && post.PublishAt.After(mod) {
+			mod = post.PublishAt
+		}
+	}
+	return mod
+}
+
+// serveContent serves b with ETag and Last-Modified headers,
+// answering conditional and range requests.
+//
+// The Content-Type is determined from the name's extension or
+// by sniffing b if one was not already set. The key identifies the representation
+// when caching its entity tag, see etag.
+func serveContent(w http.ResponseWriter, r *http.Request, name, key string, b []byte) {
+	serveContentAt(w, r, name, key, ModTime(), b)
+}
+
+// serveContentAt is serveContent reporting the modification
+// time mod, for content which changes as scheduled posts are
+// revealed. See publishedModTime.
+func serveContentAt(w http.ResponseWriter, r *http.Request, name, key string, mod time.Time, b []byte) {
+	if w.Header().Get("Content-Type") == "" {
+		w.Header().Set("Content-Type", contentType(name, b))
+	}
+	w.Header().Set("ETag", etag(key, b))
+	http.ServeContent(w, r, name, mod, bytes.NewReader(b))
+}
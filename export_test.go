@@ -0,0 +1,32 @@
+package goblog
+
+// ServeEmbedded undoes ServeLocal, pointing the handlers
+// back at the embedded content.
+func ServeEmbedded() {
+	served.Lock()
+	served.local = false
+	served.fsys = nil
+	served.cache = nil
+	served.index = nil
+	served.Unlock()
+	resetRedirects()
+}
+
+// ResetReloads replaces the reload broadcaster closed
+// once WatchLocal returns.
+func ResetReloads() {
+	reloads = &broadcaster{subs: map[chan struct{}]bool{}}
+}
+
+// CacheControl exposes cacheControl to tests.
+var CacheControl = cacheControl
+
+// SetManifest replaces the embedded Manifest.
+func SetManifest(m Manifest) {
+	manifest.once.Do(func() {})
+	manifest.logical = m
+	manifest.hashed = map[string]string{}
+	for logical, hashed := range m {
+		manifest.hashed[hashed] = logical
+	}
+}
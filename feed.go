@@ -0,0 +1,359 @@
+package goblog
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	generator = "GoBlog"
+)
+
+// rss is the root of an RSS 2.0 document.
+type rss struct {
+	XMLName   xml.Name   `xml:"rss"`
+	Version   string     `xml:"version,attr"`
+	ContentNS string     `xml:"xmlns:content,attr"`
+	Channel   rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title          string    `xml:"title"`
+	Link           string    `xml:"link"`
+	Description    string    `xml:"description"`
+	ManagingEditor string    `xml:"managingEditor,omitempty"`
+	LastBuildDate  string    `xml:"lastBuildDate,omitempty"`
+	Generator      string    `xml:"generator"`
+	Items          []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string        `xml:"title"`
+	Link        string        `xml:"link"`
+	GUID        rssGUID       `xml:"guid"`
+	Description string        `xml:"description"`
+	Content     string        `xml:"content:encoded,omitempty"`
+	Author      string        `xml:"author,omitempty"`
+	Categories  []string      `xml:"category"`
+	PubDate     string        `xml:"pubDate"`
+	Enclosure   *rssEnclosure `xml:"enclosure,omitempty"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type rssEnclosure struct {
+	URL    string `xml:"url,attr"`
+	Length int64  `xml:"length,attr"`
+	Type   string `xml:"type,attr"`
+}
+
+// atomFeed is the root of an Atom document.
+type atomFeed struct {
+	XMLName   xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title     string      `xml:"title"`
+	Subtitle  string      `xml:"subtitle,omitempty"`
+	ID        string      `xml:"id"`
+	Updated   string      `xml:"updated"`
+	Links     []atomLink  `xml:"link"`
+	Author    atomPerson  `xml:"author"`
+	Generator string      `xml:"generator"`
+	Entries   []atomEntry `xml:"entry"`
+}
+
+type atomEntry struct {
+	Title      string         `xml:"title"`
+	ID         string         `xml:"id"`
+	Updated    string         `xml:"updated"`
+	Published  string         `xml:"published"`
+	Links      []atomLink     `xml:"link"`
+	Summary    string         `xml:"summary"`
+	Categories []atomCategory `xml:"category"`
+	Content    *atomContent   `xml:"content,omitempty"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomPerson struct {
+	Name  string `xml:"name"`
+	Email string `xml:"email,omitempty"`
+}
+
+type atomContent struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+// RSSHandler serves an RSS 2.0 feed of the embedded posts.
+//
+// If the "full" query parameter is true each item will
+// include the post's rendered HTML body.
+func RSSHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		full, err := fullParam(r)
+		if err != nil {
+			http.Error(w, "could not parse full param: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		doc := rss{
+			Version:   "2.0",
+			ContentNS: "http://purl.org/rss/1.0/modules/content/",
+			Channel: rssChannel{
+				Title:       Conf.Title,
+				Link:        absURL(r, "/"),
+				Description: Conf.Description,
+				Generator:   generator,
+			},
+		}
+		if Conf.Email != "" {
+			doc.Channel.ManagingEditor = feedAuthor()
+		}
+		posts := publishedPosts()
+		if len(posts) > 0 {
+			doc.Channel.LastBuildDate = posts.LastUpdated().Format(time.RFC1123Z)
+		}
+
+		for _, post := range posts {
+			link := absURL(r, post.Path)
+			item := rssItem{
+				Title:       post.Title,
+				Link:        link,
+				GUID:        rssGUID{IsPermaLink: true, Value: link},
+				Description: post.Summary,
+				Categories:  categories(post),
+				PubDate:     post.Published.Format(time.RFC1123Z),
+			}
+			if Conf.Email != "" {
+				item.Author = feedAuthor()
+			}
+			if hero, ok := heroURL(r, post); ok {
+				item.Enclosure = &rssEnclosure{
+					URL:    hero,
+					Length: heroLength(post),
+					Type:   mime.TypeByExtension(path.Ext(post.Hero)),
+				}
+			}
+			if full {
+				item.Content, err = renderedBody(post)
+				if err != nil {
+					http.Error(w, err.Error(), http.StatusInternalServerError)
+					return
+				}
+			}
+			doc.Channel.Items = append(doc.Channel.Items, item)
+		}
+
+		w.Header().Set("Content-Type", "application/rss+xml; charset=UTF-8")
+		var buf bytes.Buffer
+		err = writeXML(&buf, doc)
+		if err != nil {
+			http.Error(w, "failed serializing: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		serveContentAt(w, r, "feed.rss", "", publishedModTime(), buf.Bytes())
+	}
+}
+
+// AtomHandler serves an Atom feed of the embedded posts.
+//
+// If the "full" query parameter is true each entry will
+// include the post's rendered HTML body.
+func AtomHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		full, err := fullParam(r)
+		if err != nil {
+			http.Error(w, "could not parse full param: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		doc := atomFeed{
+			Title:    Conf.Title,
+			Subtitle: Conf.Description,
+			ID:       absURL(r, "/"),
+			Links: []atomLink{
+				{Href: absURL(r, "/"), Rel: "alternate"},
+				{Href: absURL(r, r.URL.Path), Rel: "self", Type: "application/atom+xml"},
+			},
+			Author: atomPerson{
+				Name:  Conf.Author,
+				Email: Conf.Email,
+			},
+			Generator: generator,
+		}
+		if doc.Author.Name == "" {
+			doc.Author.Name = Conf.Title
+		}
+		posts := publishedPosts()
+		if len(posts) > 0 {
+			doc.Updated = posts.LastUpdated().Format(time.RFC3339)
+		} else {
+			doc.Updated = time.Now().Format(time.RFC3339)
+		}
+
+		for _, post := range posts {
+			link := absURL(r, post.Path)
+			entry := atomEntry{
+				Title:     post.Title,
+				ID:        link,
+				Updated:   post.Updated.Format(time.RFC3339),
+				Published: post.Published.Format(time.RFC3339),
+				Links: []atomLink{
+					{Href: link, Rel: "alternate"},
+				},
+				Summary: post.Summary,
+			}
+			for _, c := range categories(post) {
+				entry.Categories = append(entry.Categories, atomCategory{Term: c})
+			}
+			if hero, ok := heroURL(r, post); ok {
+				entry.Links = append(entry.Links, atomLink{
+					Href: hero,
+					Rel:  "enclosure",
+					Type: mime.TypeByExtension(path.Ext(post.Hero)),
+				})
+			}
+			if full {
+				body, err := renderedBody(post)
+				if err != nil {
+					http.Error(w, err.Error(), http.StatusInternalServerError)
+					return
+				}
+				entry.Content = &atomContent{Type: "html", Body: body}
+			}
+			doc.Entries = append(doc.Entries, entry)
+		}
+
+		w.Header().Set("Content-Type", "application/atom+xml; charset=UTF-8")
+		var buf bytes.Buffer
+		err = writeXML(&buf, doc)
+		if err != nil {
+			http.Error(w, "failed serializing: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		serveContentAt(w, r, "feed.atom", "", publishedModTime(), buf.Bytes())
+	}
+}
+
+// fullParam parses the optional "full" query parameter.
+func fullParam(r *http.Request) (bool, error) {
+	tmp := r.URL.Query().Get("full")
+	if tmp == "" {
+		return false, nil
+	}
+	return strconv.ParseBool(tmp)
+}
+
+// writeXML writes an xml header followed by the
+// indented encoding of v.
+func writeXML(w io.Writer, v interface{}) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	return enc.Encode(v)
+}
+
+// absURL joins the configured BaseURL with the provided
+// path.
+//
+// Feeds and sitemaps require absolute URLs, absent a BaseURL
+// the scheme and host r was made to are used. Behind a proxy
+// terminating TLS these may not be the public ones, configure
+// a BaseURL.
+func absURL(r *http.Request, p string) string {
+	base := strings.TrimSuffix(Conf.BaseURL, "/")
+	if base == "" {
+		scheme := "http"
+		if r.TLS != nil {
+			scheme = "https"
+		}
+		base = scheme + "://" + r.Host
+	}
+	return base + "/" + strings.TrimPrefix(p, "/")
+}
+
+// feedAuthor formats the configured author
+// as an RSS email address.
+func feedAuthor() string {
+	if Conf.Author == "" {
+		return Conf.Email
+	}
+	return Conf.Email + " (" + Conf.Author + ")"
+}
+
+// heroURL returns an absolute URL to the post's
+// hero image and whether the post has one.
+func heroURL(r *http.Request, post Post) (string, bool) {
+	switch {
+	case post.Hero == "" || post.Hero == "none":
+		return "", false
+	case strings.HasPrefix(post.Hero, "http://"), strings.HasPrefix(post.Hero, "https://"):
+		return post.Hero, true
+	default:
+		return absURL(r, post.Hero), true
+	}
+}
+
+// heroLength returns the size of an embedded
+// hero image or 0 if it cannot be determined.
+func heroLength(post Post) int64 {
+	f, err := postsFS().Open(strings.TrimPrefix(post.Hero, "/"))
+	if err != nil {
+		return 0
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
+
+// categories returns the post's category followed
+// by its tags.
+func categories(post Post) []string {
+	var c []string
+	if post.Category != "" {
+		c = append(c, post.Category)
+	}
+	return append(c, post.Tags...)
+}
+
+// renderedBody returns the embedded post's
+// body rendered as HTML.
+func renderedBody(post Post) (string, error) {
+	html, err := RenderPost(post.Path)
+	if err != nil {
+		return "", err
+	}
+	return string(html), nil
+}
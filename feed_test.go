@@ -0,0 +1,184 @@
+package goblog_test
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ldelossa/goblog"
+	"github.com/ldelossa/goblog/test"
+)
+
+func TestFeeds(t *testing.T) {
+	goblog.EmbeddedPostsCache = test.GenPosts(3)
+	goblog.Conf.BaseURL = "https://blog.example.com/"
+
+	t.Run("RSS", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		goblog.RSSHandler()(rec, httptest.NewRequest(http.MethodGet, "/feed.rss", nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("got: %v, want: %v", rec.Code, http.StatusOK)
+		}
+
+		var doc struct {
+			Items []struct {
+				Title string `xml:"title"`
+				Link  string `xml:"link"`
+			} `xml:"channel>item"`
+		}
+		if err := xml.NewDecoder(rec.Body).Decode(&doc); err != nil {
+			t.Fatalf("failed decoding rss: %v", err)
+		}
+		if len(doc.Items) != 3 {
+			t.Fatalf("got: %v items, want: 3", len(doc.Items))
+		}
+		test.CmpEqual(t, doc.Items[0].Title, "3")
+		test.CmpEqual(t, doc.Items[0].Link, "https://blog.example.com/drafts/3")
+	})
+
+	t.Run("Atom", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		goblog.AtomHandler()(rec, httptest.NewRequest(http.MethodGet, "/feed.atom", nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("got: %v, want: %v", rec.Code, http.StatusOK)
+		}
+
+		var doc struct {
+			Entries []struct {
+				Title string `xml:"title"`
+				ID    string `xml:"id"`
+			} `xml:"entry"`
+		}
+		if err := xml.NewDecoder(rec.Body).Decode(&doc); err != nil {
+			t.Fatalf("failed decoding atom: %v", err)
+		}
+		if len(doc.Entries) != 3 {
+			t.Fatalf("got: %v entries, want: 3", len(doc.Entries))
+		}
+		test.CmpEqual(t, doc.Entries[2].ID, "https://blog.example.com/drafts/1")
+	})
+}
+
+func TestSitemapHandler(t *testing.T) {
+	defer func(v string) { goblog.Conf.BaseURL = v }(goblog.Conf.BaseURL)
+	goblog.Conf.BaseURL = "https://blog.example.com/"
+	serveLocalPosts(t, map[string]string{
+		"first.md":  "---\ntitle: First\nsummary: s\ndate: 2021-05-28T00:00:00Z\nupdated: 2021-07-01T00:00:00Z\n---\nbody\n",
+		"second.md": "---\ntitle: Second\nsummary: s\ndate: 2021-06-28T00:00:00Z\n---\nbody\n",
+	})
+
+	rec := httptest.NewRecorder()
+	goblog.SitemapHandler([]string{"/about", "projects"})(rec, httptest.NewRequest(http.MethodGet, "/sitemap.xml", nil))
+	test.CmpEqual(t, rec.Code, http.StatusOK)
+
+	var doc struct {
+		URLs []struct {
+			Loc     string `xml:"loc"`
+			LastMod string `xml:"lastmod"`
+		} `xml:"url"`
+	}
+	if err := xml.NewDecoder(rec.Body).Decode(&doc); err != nil {
+		t.Fatalf("failed decoding sitemap: %v", err)
+	}
+	var got []string
+	for _, u := range doc.URLs {
+		got = append(got, u.Loc+" "+u.LastMod)
+	}
+	// the web root and app paths were last modified
+	// with the most recently updated post.
+	test.CmpEqual(t, got, []string{
+		"https://blog.example.com/ 2021-07-01T00:00:00Z",
+		"https://blog.example.com/about 2021-07-01T00:00:00Z",
+		"https://blog.example.com/projects 2021-07-01T00:00:00Z",
+		"https://blog.example.com/posts/second.md 2021-06-28T00:00:00Z",
+		"https://blog.example.com/posts/first.md 2021-07-01T00:00:00Z",
+	})
+}
+
+// TestFeedsWithoutBaseURL confirms feeds and sitemaps link to
+// the requested host when no BaseURL is configured.
+func TestFeedsWithoutBaseURL(t *testing.T) {
+	defer func(v string) { goblog.Conf.BaseURL = v }(goblog.Conf.BaseURL)
+	goblog.Conf.BaseURL = ""
+	serveLocalPosts(t, map[string]string{
+		"hello.md": "---\ntitle: Hello\nsummary: s\ndate: 2021-05-28T00:00:00Z\n---\nbody\n",
+	})
+
+	table := []struct {
+		Name   string
+		H      http.HandlerFunc
+		Target string
+		Want   string
+	}{
+		{"RSS", goblog.RSSHandler(), "http://blog.example.com/feed.rss", "<link>http://blog.example.com/posts/hello.md</link>"},
+		{"Atom", goblog.AtomHandler(), "https://blog.example.com/feed.atom", `href="https://blog.example.com/posts/hello.md"`},
+		{"JSON Feed", goblog.JSONFeedHandler(), "http://blog.example.com:8080/feed.json", `"url":"http://blog.example.com:8080/posts/hello.md"`},
+		{"Sitemap", goblog.SitemapHandler(nil), "https://blog.example.com/sitemap.xml", "<loc>https://blog.example.com/posts/hello.md</loc>"},
+		{"Robots", goblog.RobotsHandler(nil), "http://blog.example.com/robots.txt", "Sitemap: http://blog.example.com/sitemap.xml"},
+	}
+	for _, tt := range table {
+		t.Run(tt.Name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			tt.H(rec, httptest.NewRequest(http.MethodGet, tt.Target, nil))
+			test.CmpEqual(t, rec.Code, http.StatusOK)
+			if !strings.Contains(rec.Body.String(), tt.Want) {
+				t.Fatalf("%q not found in: %s", tt.Want, rec.Body.String())
+			}
+		})
+	}
+}
+
+// TestJSONFeedHandler confirms the feed has the shape of a
+// JSON Feed 1.1 document.
+func TestJSONFeedHandler(t *testing.T) {
+	defer func(v string) { goblog.Conf.BaseURL = v }(goblog.Conf.BaseURL)
+	goblog.Conf.BaseURL = "https://blog.example.com/"
+	serveLocalPosts(t, map[string]string{
+		"first.md":  "---\ntitle: First\nsummary: the first\ndate: 2021-05-28T00:00:00Z\nupdated: 2021-07-01T00:00:00Z\n---\n# First\n",
+		"second.md": "---\ntitle: Second\nsummary: the second\ndate: 2021-06-28T00:00:00Z\n---\n# Second\n",
+	})
+
+	rec := httptest.NewRecorder()
+	goblog.JSONFeedHandler()(rec, httptest.NewRequest(http.MethodGet, "/feed.json", nil))
+	test.CmpEqual(t, rec.Code, http.StatusOK)
+	test.CmpEqual(t, rec.Header().Get("Content-Type"), "application/feed+json; charset=UTF-8")
+
+	var doc map[string]interface{}
+	if err := json.NewDecoder(rec.Body).Decode(&doc); err != nil {
+		t.Fatalf("failed decoding json feed: %v", err)
+	}
+	test.CmpEqual(t, doc["version"], "https://jsonfeed.org/version/1.1")
+	test.CmpEqual(t, doc["feed_url"], "https://blog.example.com/feed.json")
+	items, ok := doc["items"].([]interface{})
+	if !ok || len(items) != 2 {
+		t.Fatalf("got items: %v, want 2", doc["items"])
+	}
+
+	table := []struct {
+		URL           string
+		Title         string
+		ContentText   string
+		DatePublished string
+		DateModified  string
+	}{
+		{"https://blog.example.com/posts/second.md", "Second", "# Second\n", "2021-06-28T00:00:00Z", "2021-06-28T00:00:00Z"},
+		{"https://blog.example.com/posts/first.md", "First", "# First\n", "2021-05-28T00:00:00Z", "2021-07-01T00:00:00Z"},
+	}
+	for i, tt := range table {
+		item := items[i].(map[string]interface{})
+		// items must carry an id and content_text or
+		// content_html.
+		test.CmpEqual(t, item["id"], tt.URL)
+		test.CmpEqual(t, item["url"], tt.URL)
+		test.CmpEqual(t, item["title"], tt.Title)
+		test.CmpEqual(t, item["content_text"], tt.ContentText)
+		if _, ok := item["content_html"]; ok {
+			t.Fatalf("unexpected content_html: %v", item)
+		}
+		test.CmpEqual(t, item["date_published"], tt.DatePublished)
+		test.CmpEqual(t, item["date_modified"], tt.DateModified)
+	}
+}
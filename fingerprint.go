@@ -0,0 +1,302 @@
+package goblog
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	// the directory in GeneratedFS holding fingerprinted
+	// web root assets.
+	fingerprintDir = "generated/fingerprinted"
+	// the manifest mapping logical web root paths to
+	// their fingerprinted paths.
+	fingerprintManifest = fingerprintDir + "/manifest.json"
+)
+
+var (
+	// matches src and href attributes in HTML.
+	htmlRef = regexp.MustCompile(`(?i)(?:src|href)\s*=\s*["']([^"']+)["']`)
+	// matches url() and @import references in CSS.
+	cssRef = regexp.MustCompile(`(?i)(?:url\(\s*["']?([^"')]+)["']?\s*\)|@import\s+["']([^"']+)["'])`)
+)
+
+// Manifest maps the logical paths of web root assets, such as
+// "/app.js", to their fingerprinted paths, such as "/app.3f2a9c1b.js".
+type Manifest map[string]string
+
+// manifest is the embedded Manifest and its reverse,
+// loaded on first use.
+var manifest struct {
+	once    sync.Once
+	logical Manifest
+	hashed  map[string]string
+}
+
+// loadManifest returns the embedded Manifest and a map of
+// fingerprinted paths back to their logical paths.
+//
+// Both are empty if the web root was not fingerprinted.
+func loadManifest() (Manifest, map[string]string) {
+	manifest.once.Do(func() {
+		manifest.logical = Manifest{}
+		manifest.hashed = map[string]string{}
+		b, err := fs.ReadFile(GeneratedFS, fingerprintManifest)
+		if err != nil {
+			return
+		}
+		if err := json.Unmarshal(b, &manifest.logical); err != nil {
+			return
+		}
+		for logical, hashed := range manifest.logical {
+			manifest.hashed[hashed] = logical
+		}
+	})
+	return manifest.logical, manifest.hashed
+}
+
+// fingerprinted reports whether the embedded file name is an
+// asset fingerprinted by 'goblog build'.
+//
+// Only names in the Manifest are, names which merely look
+// hashed, such as "hero-20230101.png", may change.
+func fingerprinted(name string) bool {
+	if !strings.HasPrefix(name, fingerprintDir+"/") {
+		return false
+	}
+	_, hashed := loadManifest()
+	_, ok := hashed[strings.TrimPrefix(name, fingerprintDir)]
+	return ok
+}
+
+// fingerprint copies each asset in the local web root into the
+// Generated directory with a content hash in its name.
+//
+// References to fingerprinted assets in HTML and CSS files are
+// rewritten. HTML files are not fingerprinted, as they are entry
+// points, but their rewritten copies are stored alongside.
+//
+// A Manifest of the fingerprinted assets is written last.
+func fingerprint() error {
+	var files []string
+	err := filepath.Walk(Web, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if info.IsDir() || strings.HasPrefix(info.Name(), ".") {
+			return nil
+		}
+		files = append(files, "/"+filepath.ToSlash(strings.TrimPrefix(p, Web+"/")))
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// assets without references are hashed first, then CSS
+	// which may reference them and each other, then HTML
+	// which may reference any of them.
+	rank := func(f string) int {
+		switch {
+		case isHTML(f):
+			return 2
+		case strings.ToLower(path.Ext(f)) == ".css":
+			return 1
+		}
+		return 0
+	}
+	sort.SliceStable(files, func(i, j int) bool {
+		return rank(files[i]) < rank(files[j])
+	})
+	i := sort.Search(len(files), func(i int) bool { return rank(files[i]) >= 1 })
+	j := sort.Search(len(files), func(i int) bool { return rank(files[i]) >= 2 })
+	if err := sortCSS(files[i:j]); err != nil {
+		return err
+	}
+
+	m := Manifest{}
+	for _, f := range files {
+		b, err := os.ReadFile(filepath.Join(Web, filepath.FromSlash(f)))
+		if err != nil {
+			return err
+		}
+
+		switch rank(f) {
+		case 1:
+			b = rewriteRefs(b, f, cssRef, m)
+		case 2:
+			b = rewriteRefs(b, f, htmlRef, m)
+			if err := writeFingerprinted(f, b); err != nil {
+				return err
+			}
+			continue
+		}
+
+		sum := sha256.Sum256(b)
+		ext := path.Ext(f)
+		hashed := strings.TrimSuffix(f, ext) + "." + hex.EncodeToString(sum[:4]) + ext
+		if err := writeFingerprinted(hashed, b); err != nil {
+			return err
+		}
+		m[f] = hashed
+	}
+
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(Src, fingerprintManifest), b, 0660)
+}
+
+// sortCSS orders the CSS files at the web root paths css so
+// each follows the CSS files it references, which are then
+// hashed before their references are rewritten.
+//
+// References forming a cycle can't all be rewritten, the
+// files in a cycle keep their walk order.
+func sortCSS(css []string) error {
+	deps := map[string][]string{}
+	for _, f := range css {
+		deps[f] = nil
+	}
+	for _, f := range css {
+		b, err := os.ReadFile(filepath.Join(Web, filepath.FromSlash(f)))
+		if err != nil {
+			return err
+		}
+		for _, match := range cssRef.FindAllSubmatch(b, -1) {
+			ref := string(match[1])
+			if ref == "" {
+				ref = string(match[2])
+			}
+			logical, _, ok := resolveRef(ref, f)
+			if _, css := deps[logical]; ok && css && logical != f {
+				deps[f] = append(deps[f], logical)
+			}
+		}
+	}
+
+	sorted := make([]string, 0, len(css))
+	visited := map[string]bool{}
+	var visit func(f string)
+	visit = func(f string) {
+		if visited[f] {
+			return
+		}
+		visited[f] = true
+		for _, dep := range deps[f] {
+			visit(dep)
+		}
+		sorted = append(sorted, f)
+	}
+	for _, f := range css {
+		visit(f)
+	}
+	copy(css, sorted)
+	return nil
+}
+
+// isHTML reports whether the file at p is an HTML document.
+func isHTML(p string) bool {
+	switch strings.ToLower(path.Ext(p)) {
+	case ".html", ".htm":
+		return true
+	}
+	return false
+}
+
+// writeFingerprinted writes b to the web root path p within
+// the fingerprinted directory.
+func writeFingerprinted(p string, b []byte) error {
+	dest := filepath.Join(Src, fingerprintDir, filepath.FromSlash(p))
+	if err := os.MkdirAll(filepath.Dir(dest), 0770); err != nil {
+		return fmt.Errorf("failed creating directory for %v: %w", dest, err)
+	}
+	return os.WriteFile(dest, b, 0660)
+}
+
+// rewriteRefs rewrites references matched by re in the file at
+// web root path f to the fingerprinted paths found in m.
+//
+// Relative references are resolved against f's directory and
+// remain relative once rewritten.
+func rewriteRefs(b []byte, f string, re *regexp.Regexp, m Manifest) []byte {
+	var out []byte
+	last := 0
+	for _, match := range re.FindAllSubmatchIndex(b, -1) {
+		// the reference is the first group which matched.
+		start, end := -1, -1
+		for g := 2; g+1 < len(match); g += 2 {
+			if match[g] != -1 {
+				start, end = match[g], match[g+1]
+				break
+			}
+		}
+		if start == -1 {
+			continue
+		}
+
+		ref := string(b[start:end])
+		hashed, ok := lookupRef(ref, f, m)
+		if !ok {
+			continue
+		}
+		out = append(out, b[last:start]...)
+		out = append(out, hashed...)
+		last = end
+	}
+	return append(out, b[last:]...)
+}
+
+// lookupRef resolves ref, found in the file at web root path
+// f, and returns it rewritten to its fingerprinted path.
+func lookupRef(ref, f string, m Manifest) (string, bool) {
+	logical, suffix, ok := resolveRef(ref, f)
+	if !ok {
+		return "", false
+	}
+	hashed, ok := m[logical]
+	if !ok {
+		return "", false
+	}
+	// keep any query or fragment, and as only the file
+	// name changes, the reference's directory as written.
+	ref = strings.TrimSuffix(ref, suffix)
+	return strings.TrimSuffix(ref, path.Base(ref)) + path.Base(hashed) + suffix, true
+}
+
+// resolveRef returns the web root path referenced by ref,
+// found in the file at web root path f, and any query or
+// fragment of ref.
+//
+// References to other hosts are not resolved.
+func resolveRef(ref, f string) (logical, suffix string, ok bool) {
+	if strings.Contains(ref, "://") || strings.HasPrefix(ref, "//") || strings.HasPrefix(ref, "data:") {
+		return "", "", false
+	}
+	if i := strings.IndexAny(ref, "?#"); i != -1 {
+		ref, suffix = ref[:i], ref[i:]
+	}
+	if ref == "" {
+		return "", "", false
+	}
+
+	logical = ref
+	if !strings.HasPrefix(ref, "/") {
+		logical = path.Join(path.Dir(f), ref)
+	}
+	return path.Clean(logical), suffix, true
+}
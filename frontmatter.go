@@ -0,0 +1,218 @@
+package goblog
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"reflect"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// The file formats a post may be stored in.
+const (
+	// a YAML document holding the post's metadata with its
+	// Markdown body in the "mark_down" key.
+	PostExt = ".post"
+	// a Markdown document preceded by the post's metadata as
+	// YAML front matter between "---" lines.
+	MarkdownExt = ".md"
+)
+
+// frontMatterDelim opens and closes the front
+// matter of a Markdown post.
+const frontMatterDelim = "---"
+
+// ErrNoFrontMatter is returned by DecodePost for a Markdown
+// file which does not begin with front matter.
+//
+// Such a file is not a post, the caches and handlers treat it
+// as an asset, such as a README, alongside the posts.
+var ErrNoFrontMatter = errors.New("markdown post must begin with '---' front matter")
+
+// IsPostFile reports whether the file name is in
+// one of the post formats.
+func IsPostFile(name string) bool {
+	switch path.Ext(name) {
+	case PostExt, MarkdownExt:
+		return true
+	}
+	return false
+}
+
+// DecodePost decodes a post from r in the format
+// indicated by name's extension.
+func DecodePost(r io.Reader, name string) (Post, error) {
+	var post Post
+	if path.Ext(name) != MarkdownExt {
+		err := yaml.NewDecoder(r).Decode(&post)
+		return post, err
+	}
+
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return post, err
+	}
+	meta, body, err := splitFrontMatter(b)
+	if err != nil {
+		return post, err
+	}
+	if err := yaml.Unmarshal(meta, &post); err != nil {
+		return post, err
+	}
+	post.MarkDown = markdownNode(string(body))
+	return post, nil
+}
+
+// EncodePost writes the post to w in the format indicated
+// by its Path's extension.
+//
+// Encoding a post decoded by DecodePost in either format
+// retains its metadata and Markdown body exactly.
+func EncodePost(w io.Writer, post Post) error {
+	if path.Ext(post.Path) != MarkdownExt {
+		return yaml.NewEncoder(w).Encode(post)
+	}
+
+	body := post.MarkDown.Value
+	post.MarkDown = yaml.Node{}
+	meta, err := yaml.Marshal(post)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	buf.WriteString(frontMatterDelim + "\n")
+	buf.Write(meta)
+	buf.WriteString(frontMatterDelim + "\n")
+	buf.WriteString(body)
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+// splitFrontMatter splits a Markdown post into its
+// YAML front matter and body.
+func splitFrontMatter(b []byte) (meta, body []byte, err error) {
+	// editors may save a byte order mark.
+	b = bytes.TrimPrefix(b, []byte("\ufeff"))
+	line, rest := cutLine(b)
+	if string(bytes.TrimRight(line, " \t")) != frontMatterDelim {
+		return nil, nil, ErrNoFrontMatter
+	}
+	start := len(b) - len(rest)
+	for len(rest) > 0 {
+		line, next := cutLine(rest)
+		if string(bytes.TrimRight(line, " \t")) == frontMatterDelim {
+			end := len(b) - len(rest)
+			return b[start:end], next, nil
+		}
+		rest = next
+	}
+	return nil, nil, fmt.Errorf("markdown post front matter is not closed by '---'")
+}
+
+// cutLine returns the first line of b, without its line
+// ending, and the remainder of b.
+func cutLine(b []byte) (line, rest []byte) {
+	i := bytes.IndexByte(b, '\n')
+	if i == -1 {
+		return b, nil
+	}
+	return bytes.TrimSuffix(b[:i], []byte("\r")), b[i+1:]
+}
+
+// markdownNode returns the YAML node holding a
+// post's Markdown body.
+func markdownNode(md string) yaml.Node {
+	return yaml.Node{
+		Kind:  yaml.ScalarNode,
+		Style: yaml.LiteralStyle,
+		Tag:   "!!str",
+		Value: md,
+	}
+}
+
+// ConvertPost rewrites the post file at p, a path on the
+// local filesystem, into the format indicated by ext and
+// returns the path of the new file.
+//
+// The original file is only removed once the new file is
+// confirmed to decode to the same post.
+func ConvertPost(p, ext string) (string, error) {
+	if ext != PostExt && ext != MarkdownExt {
+		return "", fmt.Errorf("unsupported post format: %v", ext)
+	}
+	if path.Ext(p) == ext {
+		return p, nil
+	}
+
+	orig, err := readPostFile(p)
+	if err != nil {
+		return "", err
+	}
+
+	dest := strings.TrimSuffix(p, path.Ext(p)) + ext
+	f, err := os.OpenFile(dest, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0660)
+	if err != nil {
+		return "", fmt.Errorf("failed creating %v: %w", dest, err)
+	}
+	converted := orig
+	converted.Path = dest
+	err = EncodePost(f, converted)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(dest)
+		return "", fmt.Errorf("failed writing %v: %w", dest, err)
+	}
+
+	check, err := readPostFile(dest)
+	if err != nil || !samePost(orig, check) {
+		os.Remove(dest)
+		return "", fmt.Errorf("converting %v would lose content, leaving it as is", p)
+	}
+	if err := os.Remove(p); err != nil {
+		return "", fmt.Errorf("failed removing %v: %w", p, err)
+	}
+	return dest, nil
+}
+
+func readPostFile(p string) (Post, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return Post{}, err
+	}
+	defer f.Close()
+	post, err := DecodePost(f, p)
+	if err != nil {
+		return post, fmt.Errorf("failed reading post %v: %w", p, err)
+	}
+	return post, nil
+}
+
+// samePost reports whether a and b hold the same metadata
+// and Markdown body, regardless of their format.
+func samePost(a, b Post) bool {
+	if a.MarkDown.Value != b.MarkDown.Value {
+		return false
+	}
+	for _, t := range [][2]*time.Time{
+		{&a.Published, &b.Published},
+		{&a.Updated, &b.Updated},
+		{&a.Created, &b.Created},
+		{&a.PublishAt, &b.PublishAt},
+	} {
+		if !t[0].Equal(*t[1]) {
+			return false
+		}
+		*t[0], *t[1] = t[0].UTC(), t[1].UTC()
+	}
+	a.Path, b.Path = "", ""
+	a.MarkDown, b.MarkDown = yaml.Node{}, yaml.Node{}
+	return reflect.DeepEqual(a, b)
+}
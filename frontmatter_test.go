@@ -0,0 +1,64 @@
+package goblog_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ldelossa/goblog"
+	"github.com/ldelossa/goblog/test"
+)
+
+func TestDecodeMarkdownPost(t *testing.T) {
+	const md = "---\ntitle: Hello World\nsummary: a summary\ndate: 2021-05-28T00:00:00Z\ntags: [go]\n---\n# Hello\n\n---\n\nbody\n"
+	cleanup, _, err := test.HijackEnviroment("posts")
+	if err != nil {
+		t.Fatalf("could not hijack environment: %v", err)
+	}
+	defer cleanup()
+
+	p := filepath.Join(goblog.Posts, "hello_world.md")
+	if err := os.WriteFile(p, []byte(md), 0660); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	f, err := os.Open(p)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	post, err := goblog.DecodePost(f, p)
+	f.Close()
+	if err != nil {
+		t.Fatalf("failed decoding: %v", err)
+	}
+	test.CmpEqual(t, post.Title, "Hello World")
+	test.CmpEqual(t, post.Published.Equal(time.Date(2021, 5, 28, 0, 0, 0, 0, time.UTC)), true)
+	test.CmpEqual(t, post.Tags, []string{"go"})
+	test.CmpEqual(t, post.MarkDown.Value, "# Hello\n\n---\n\nbody\n")
+
+	// converting to .post and back must reproduce
+	// the original file.
+	dest, err := goblog.ConvertPost(p, goblog.PostExt)
+	if err != nil {
+		t.Fatalf("failed converting to .post: %v", err)
+	}
+	if _, err := os.Stat(p); !os.IsNotExist(err) {
+		t.Fatalf("expected %v to be removed", p)
+	}
+	dest, err = goblog.ConvertPost(dest, goblog.MarkdownExt)
+	if err != nil {
+		t.Fatalf("failed converting to .md: %v", err)
+	}
+	b, err := os.ReadFile(dest)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	test.CmpEqual(t, string(b), "---\nhero: \"\"\ntitle: Hello World\nsummary: a summary\ndate: 2021-05-28T00:00:00Z\ntags:\n    - go\n---\n# Hello\n\n---\n\nbody\n")
+
+	_, err = goblog.DecodePost(strings.NewReader("# Hello\n"), "no_front_matter.md")
+	if err == nil {
+		t.Fatalf("expected an error for a post without front matter")
+	}
+}
@@ -0,0 +1,171 @@
+package goblog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Generate derives content from the local src tree and writes
+// it to the Generated directory so it may be embedded into the
+// next GoBlog binary.
+//
+// Each local post is rendered to HTML and a summary index, the
+// same document served by SummaryHandler, is written alongside.
+//
+// If enabled in the local config the web root is fingerprinted.
+//
+// Finally gzip and brotli compressed siblings of the web root,
+// post assets, and the generated content are written.
+//
+// Any previously generated content is removed first.
+func Generate(ctx context.Context) error {
+	if err := os.MkdirAll(Generated, 0770); err != nil {
+		return fmt.Errorf("failed creating generated directory: %w", err)
+	}
+	entries, err := os.ReadDir(Generated)
+	if err != nil {
+		return fmt.Errorf("failed reading generated directory: %w", err)
+	}
+	for _, e := range entries {
+		// keep the file which allows an "empty" generated
+		// directory to be embedded.
+		if e.Name() == ".empty" {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(Generated, e.Name())); err != nil {
+			return fmt.Errorf("failed removing generated content: %w", err)
+		}
+	}
+
+	// the local posts may have changed since our cache
+	// was created, walk them again.
+	posts, err := NewLocalPostsCache(ctx)
+	if err != nil {
+		return fmt.Errorf("failed reading local posts: %w", err)
+	}
+
+	for _, post := range posts {
+		html, err := RenderMarkdown(post.MarkDown.Value)
+		if err != nil {
+			return fmt.Errorf("failed rendering post %v: %w", post.Path, err)
+		}
+		dest := filepath.Join(Src, generatedPostPath(post.Path))
+		if err := os.MkdirAll(filepath.Dir(dest), 0770); err != nil {
+			return fmt.Errorf("failed creating directory for %v: %w", dest, err)
+		}
+		if err := os.WriteFile(dest, html, 0660); err != nil {
+			return fmt.Errorf("failed writing rendered post %v: %w", dest, err)
+		}
+	}
+
+	summaries := make([]Post, 0, len(posts))
+	for _, post := range posts {
+		summaries = append(summaries, summary(post))
+	}
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(summaries); err != nil {
+		return fmt.Errorf("failed encoding summary index: %w", err)
+	}
+	err = os.WriteFile(filepath.Join(Src, generatedSummaries), buf.Bytes(), 0660)
+	if err != nil {
+		return fmt.Errorf("failed writing summary index: %w", err)
+	}
+
+	// a tree without a local config has nothing to
+	// opt into fingerprinting.
+	conf, err := LocalConfig()
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	if conf.Fingerprint {
+		if err := fingerprint(); err != nil {
+			return fmt.Errorf("failed fingerprinting web root: %w", err)
+		}
+	}
+
+	if err := precompress(); err != nil {
+		return fmt.Errorf("failed precompressing content: %w", err)
+	}
+	return nil
+}
+
+// precompress writes compressed siblings of the web root, post
+// assets, and generated content into the Generated directory.
+//
+// A sibling is only written when it is smaller than the
+// original file.
+func precompress() error {
+	var files []string
+	for _, dir := range []string{Web, Posts, Generated} {
+		err := filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+			if err != nil {
+				if os.IsNotExist(err) {
+					return nil
+				}
+				return err
+			}
+			if info.IsDir() || strings.HasPrefix(info.Name(), ".") {
+				return nil
+			}
+			// posts are served decoded, never as the raw file.
+			if IsPostFile(p) || !compressible(p) {
+				return nil
+			}
+			files = append(files, strings.TrimPrefix(p, Src+"/"))
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, f := range files {
+		b, err := os.ReadFile(filepath.Join(Src, f))
+		if err != nil {
+			return err
+		}
+		for _, enc := range encodings {
+			compressed, err := compress(b, enc)
+			if err != nil {
+				return fmt.Errorf("failed compressing %v: %w", f, err)
+			}
+			if len(compressed) >= len(b) {
+				continue
+			}
+			dest := filepath.Join(Src, compressedPath(f, enc))
+			if err := os.MkdirAll(filepath.Dir(dest), 0770); err != nil {
+				return err
+			}
+			if err := os.WriteFile(dest, compressed, 0660); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// summary returns a copy of the post holding only
+// the metadata served by SummaryHandler.
+func summary(post Post) Post {
+	return Post{
+		Path:      post.Path,
+		Title:     post.Title,
+		Slug:      post.Slug,
+		Summary:   post.Summary,
+		Published: post.Published,
+		Updated:   post.Updated,
+		Created:   post.Created,
+		Hero:      post.Hero,
+		Tags:      post.Tags,
+		Category:  post.Category,
+		Aliases:   post.Aliases,
+		PublishAt: post.PublishAt,
+	}
+}
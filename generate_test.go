@@ -0,0 +1,254 @@
+package goblog_test
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ldelossa/goblog"
+	"github.com/ldelossa/goblog/test"
+)
+
+func TestGenerate(t *testing.T) {
+	cleanup, _, err := test.HijackEnviroment("posts", "web")
+	if err != nil {
+		t.Fatalf("could not hijack environment: %v", err)
+	}
+	defer cleanup()
+
+	const post = `title: Hello World
+summary: a summary
+date: 2021-05-28T00:00:00Z
+mark_down: "# Hello"
+`
+	err = os.WriteFile(filepath.Join(goblog.Posts, "hello_world.post"), []byte(post), 0660)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	index := strings.Repeat("<p>hello</p>\n", 100)
+	err = os.WriteFile(filepath.Join(goblog.Web, "index.html"), []byte(index), 0660)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if err := goblog.Generate(context.Background()); err != nil {
+		t.Fatalf("failed generating: %v", err)
+	}
+
+	html, err := os.ReadFile(filepath.Join(goblog.Generated, "posts", "hello_world.post.html"))
+	if err != nil {
+		t.Fatalf("rendered post not found: %v", err)
+	}
+	if !strings.Contains(string(html), `<h1 id="hello">Hello</h1>`) {
+		t.Fatalf("unexpected rendered post: %s", html)
+	}
+
+	f, err := os.Open(filepath.Join(goblog.Generated, "summaries.json"))
+	if err != nil {
+		t.Fatalf("summary index not found: %v", err)
+	}
+	defer f.Close()
+	var summaries []goblog.Post
+	if err := json.NewDecoder(f).Decode(&summaries); err != nil {
+		t.Fatalf("failed decoding summary index: %v", err)
+	}
+	if len(summaries) != 1 {
+		t.Fatalf("got: %v summaries, want: 1", len(summaries))
+	}
+	test.CmpEqual(t, summaries[0].Path, "posts/hello_world.post")
+
+	for _, ext := range []string{".gz", ".br"} {
+		_, err = os.Stat(filepath.Join(goblog.Generated, "compressed", "web", "index.html"+ext))
+		if err != nil {
+			t.Fatalf("precompressed web file not found: %v", err)
+		}
+	}
+}
+
+func TestGenerateFingerprint(t *testing.T) {
+	cleanup, _, err := test.HijackEnviroment("posts", "web", "config")
+	if err != nil {
+		t.Fatalf("could not hijack environment: %v", err)
+	}
+	defer cleanup()
+
+	files := map[string]string{
+		"config/config.yaml": "fingerprint: true\n",
+		"web/index.html":     `<link href="/css/app.css?v=1"><script src="app.js"></script><a href="https://example.com/app.js">`,
+		"web/app.js":         `console.log("hello")`,
+		"web/css/app.css":    `body { background: url("../img/bg.png"); }`,
+		"web/img/bg.png":     "png",
+	}
+	for name, content := range files {
+		p := filepath.Join(goblog.Src, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0770); err != nil {
+			t.Fatalf("%v", err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0660); err != nil {
+			t.Fatalf("%v", err)
+		}
+	}
+
+	if err := goblog.Generate(context.Background()); err != nil {
+		t.Fatalf("failed generating: %v", err)
+	}
+
+	dir := filepath.Join(goblog.Generated, "fingerprinted")
+	b, err := os.ReadFile(filepath.Join(dir, "manifest.json"))
+	if err != nil {
+		t.Fatalf("manifest not found: %v", err)
+	}
+	var m goblog.Manifest
+	if err := json.Unmarshal(b, &m); err != nil {
+		t.Fatalf("failed decoding manifest: %v", err)
+	}
+	if len(m) != 3 {
+		t.Fatalf("got: %v manifest entries, want: 3", len(m))
+	}
+	for logical, hashed := range m {
+		if _, err := os.Stat(filepath.Join(dir, hashed)); err != nil {
+			t.Fatalf("fingerprinted asset for %v not found: %v", logical, err)
+		}
+	}
+
+	css, err := os.ReadFile(filepath.Join(dir, m["/css/app.css"]))
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	want := `url("../img/` + filepath.Base(m["/img/bg.png"]) + `")`
+	if !strings.Contains(string(css), want) {
+		t.Fatalf("got: %s, want reference: %s", css, want)
+	}
+
+	index, err := os.ReadFile(filepath.Join(dir, "index.html"))
+	if err != nil {
+		t.Fatalf("rewritten index.html not found: %v", err)
+	}
+	for _, want := range []string{
+		`href="` + m["/css/app.css"] + `?v=1"`,
+		`src="` + filepath.Base(m["/app.js"]) + `"`,
+		`href="https://example.com/app.js"`,
+	} {
+		if !strings.Contains(string(index), want) {
+			t.Fatalf("got: %s, want reference: %s", index, want)
+		}
+	}
+}
+
+// TestGenerateDistinctPaths confirms posts differing only in
+// their format, and post assets named like rendered posts,
+// don't overwrite each other's generated content.
+func TestGenerateDistinctPaths(t *testing.T) {
+	cleanup, _, err := test.HijackEnviroment("posts", "web")
+	if err != nil {
+		t.Fatalf("could not hijack environment: %v", err)
+	}
+	defer cleanup()
+
+	asset := strings.Repeat("<p>asset</p>\n", 100)
+	for name, content := range map[string]string{
+		"foo.post": "title: Foo\nsummary: s\ndate: 2021-05-28T00:00:00Z\nmark_down: \"" + strings.Repeat("yaml post ", 100) + "\"\n",
+		"foo.md":   "---\ntitle: Foo\nsummary: s\ndate: 2021-05-29T00:00:00Z\n---\n" + strings.Repeat("markdown post ", 100) + "\n",
+		"foo.html": asset,
+	} {
+		if err := os.WriteFile(filepath.Join(goblog.Posts, name), []byte(content), 0660); err != nil {
+			t.Fatalf("%v", err)
+		}
+	}
+
+	if err := goblog.Generate(context.Background()); err != nil {
+		t.Fatalf("failed generating: %v", err)
+	}
+
+	for name, want := range map[string]string{
+		"posts/foo.post.html": "yaml post",
+		"posts/foo.md.html":   "markdown post",
+	} {
+		html, err := os.ReadFile(filepath.Join(goblog.Generated, name))
+		if err != nil {
+			t.Fatalf("rendered post not found: %v", err)
+		}
+		if !strings.Contains(string(html), want) {
+			t.Fatalf("%v: unexpected rendered post: %s", name, html)
+		}
+	}
+
+	gz, err := os.Open(filepath.Join(goblog.Generated, "compressed", "posts", "foo.html.gz"))
+	if err != nil {
+		t.Fatalf("precompressed asset not found: %v", err)
+	}
+	defer gz.Close()
+	r, err := gzip.NewReader(gz)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	b, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	test.CmpEqual(t, string(b), asset)
+	for _, name := range []string{"posts/foo.md.html.gz", "posts/foo.post.html.gz"} {
+		if _, err := os.Stat(filepath.Join(goblog.Generated, "compressed", "generated", name)); err != nil {
+			t.Fatalf("precompressed rendered post not found: %v", err)
+		}
+	}
+}
+
+// TestGenerateFingerprintCSSImports confirms CSS referencing
+// CSS is rewritten regardless of the order files are walked.
+func TestGenerateFingerprintCSSImports(t *testing.T) {
+	cleanup, _, err := test.HijackEnviroment("posts", "web", "config")
+	if err != nil {
+		t.Fatalf("could not hijack environment: %v", err)
+	}
+	defer cleanup()
+
+	files := map[string]string{
+		"config/config.yaml": "fingerprint: true\n",
+		"web/css/a.css":      `@import "b.css"; body { color: red; }`,
+		"web/css/b.css":      `@import url("/css/z.css"); p { color: blue; }`,
+		"web/css/z.css":      `a { color: green; }`,
+	}
+	for name, content := range files {
+		p := filepath.Join(goblog.Src, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0770); err != nil {
+			t.Fatalf("%v", err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0660); err != nil {
+			t.Fatalf("%v", err)
+		}
+	}
+
+	if err := goblog.Generate(context.Background()); err != nil {
+		t.Fatalf("failed generating: %v", err)
+	}
+
+	dir := filepath.Join(goblog.Generated, "fingerprinted")
+	b, err := os.ReadFile(filepath.Join(dir, "manifest.json"))
+	if err != nil {
+		t.Fatalf("manifest not found: %v", err)
+	}
+	var m goblog.Manifest
+	if err := json.Unmarshal(b, &m); err != nil {
+		t.Fatalf("failed decoding manifest: %v", err)
+	}
+
+	for f, want := range map[string]string{
+		"/css/a.css": `@import "` + filepath.Base(m["/css/b.css"]) + `"`,
+		"/css/b.css": `@import url("` + m["/css/z.css"] + `")`,
+	} {
+		css, err := os.ReadFile(filepath.Join(dir, m[f]))
+		if err != nil {
+			t.Fatalf("%v", err)
+		}
+		if !strings.Contains(string(css), want) {
+			t.Fatalf("%v: got: %s, want reference: %s", f, css, want)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package goblog
+
+import (
+	"embed"
+	"path"
+)
+
+//go:embed generated/*
+var GeneratedFS embed.FS
+
+const (
+	// the embedded path of the pre-rendered summary index.
+	generatedSummaries = "generated/summaries.json"
+	// the embedded directory holding precompressed siblings,
+	// see compressedPath.
+	compressedDir = "generated/compressed"
+)
+
+// generatedPostPath returns the path in GeneratedFS where the
+// pre-rendered HTML of the post at p is stored.
+//
+// The post's extension is kept, posts differing only in
+// their format are rendered to separate files.
+func generatedPostPath(p string) string {
+	return path.Join("generated", p+".html")
+}
@@ -1,26 +1,39 @@
 package goblog
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
-	"io"
 	"io/fs"
 	"net/http"
 	"path"
 	"path/filepath"
 	"strconv"
 	"strings"
-
-	"gopkg.in/yaml.v3"
+	"time"
 )
 
+// WebHandler serves the embedded web root.
+//
+// Requests for the provided front-end application paths
+// are served the web root's index.html.
+//
+// Responses carry a Content-Type and the Cache-Control
+// assigned by the configured CachePolicy.
+//
+// If the web root was fingerprinted at build time its hashed
+// assets are served alongside the original files and HTML
+// files are served with references rewritten to the hashed
+// assets.
 func WebHandler(appPaths []string) http.HandlerFunc {
 	const (
 		webPath = "web"
 	)
+	_, hashed := loadManifest()
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodGet {
 			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
 		}
 
 		// any requested web files will expect to be
@@ -40,7 +53,21 @@ func WebHandler(appPaths []string) http.HandlerFunc {
 			}
 		}
 
-		f, err := WebFS.Open(p)
+		index := p == path.Join(webPath, "index.html")
+
+		// fingerprinted assets and the HTML rewritten to
+		// reference them are embedded in GeneratedFS.
+		fsys := fs.FS(webFS())
+		if _, ok := hashed[r.URL.Path]; ok {
+			fsys, p = generatedFS(), path.Join(fingerprintDir, r.URL.Path)
+		} else if len(hashed) > 0 && isHTML(p) {
+			fp := path.Join(fingerprintDir, strings.TrimPrefix(p, webPath))
+			if _, err := fs.Stat(generatedFS(), fp); err == nil {
+				fsys, p = generatedFS(), fp
+			}
+		}
+
+		b, err := fs.ReadFile(fsys, p)
 		var fsErr *fs.PathError
 		switch {
 		case errors.As(err, &fsErr):
@@ -51,85 +78,267 @@ func WebHandler(appPaths []string) http.HandlerFunc {
 			return
 		}
 
-		io.Copy(w, f)
+		if local() && isHTML(p) {
+			b = injectReloadScript(b)
+		}
+
+		w.Header().Set("Cache-Control", cacheControl(p, index))
+		serveEmbedded(w, r, p, b)
 	}
 }
 
+// SummaryHandler serves the summaries of embedded posts
+// in date order, newest first.
+//
+// The "limit" and "offset" query parameters page through the
+// summaries. When a limit is provided a Link header points to
+// the next and previous pages using an opaque "cursor" parameter.
+//
+// The "before" and "after" query parameters accept a date or
+// RFC3339 timestamp and the "year" and "month" parameters select
+// an archive period.
+//
+// The X-Total-Count header holds the number of summaries matching
+// the filters before paging.
 func SummaryHandler() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodGet {
 			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
 		}
 
-		var lim int
-		var err error
-		tmp := r.URL.Query().Get("limit")
-		if tmp != "" {
-			lim, err = strconv.Atoi(tmp)
-			if err != nil {
-				http.Error(w, "could not parse limit param: "+err.Error(), http.StatusBadRequest)
-				return
-			}
+		q, err := parsePageQuery(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
 		}
 
-		var summaries []Post
-		switch {
-		case lim == 0:
-			summaries = EmbeddedPostsCache[:]
-		case lim > len(EmbeddedPostsCache):
-			lim = len(EmbeddedPostsCache)
-			summaries = EmbeddedPostsCache[:lim]
-		default:
-			summaries = EmbeddedPostsCache[:lim]
+		posts := postsCache()
+		published := posts.Published(time.Now())
+		filtered := q.filter(published)
+		w.Header().Set("X-Total-Count", strconv.Itoa(len(filtered)))
+		if links := q.links(r, len(filtered)); links != "" {
+			w.Header().Set("Link", links)
+		}
+		w.Header().Set("Content-Type", "application/json")
+
+		// serve the pre-rendered summary index when
+		// all summaries are requested and none were
+		// scheduled after the binary was built.
+		mod := publishedModTime()
+		if q.limit == 0 && q.offset == 0 && !q.filtered() && len(published) == len(posts) && mod.Equal(ModTime()) {
+			if b, err := fs.ReadFile(generatedFS(), generatedSummaries); err == nil {
+				serveEmbedded(w, r, generatedSummaries, b)
+				return
+			}
 		}
 
-		err = json.NewEncoder(w).Encode(summaries)
+		var buf bytes.Buffer
+		err = json.NewEncoder(&buf).Encode(q.page(filtered))
 		if err != nil {
 			http.Error(w, "failed serializing: "+err.Error(), http.StatusInternalServerError)
+			return
 		}
+		serveContentAt(w, r, "summaries.json", "", mod, buf.Bytes())
 	}
-
 }
 
+const (
+	formatMarkdown = "markdown"
+	formatHTML     = "html"
+	formatJSON     = "json"
+)
+
+// PostsHandler serves embedded posts and their assets.
+//
+// Posts are served as Markdown unless the "format" query
+// parameter is "html" or, absent the parameter, the client
+// accepts "text/html". In these cases the post is served
+// as rendered HTML.
+//
+// A "format" of "json", or a request for "/posts/{path}/meta",
+// serves the post's metadata and body as a PostDetail.
 func PostsHandler() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodGet {
 			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
 		}
 
+		format, err := postFormat(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// absent a "format" the post's format is
+		// negotiated from the Accept header.
+		negotiated := r.URL.Query().Get("format") == ""
 		post := r.URL.Path
 		post = strings.Trim(post, "/")
+		if strings.HasSuffix(post, "/meta") {
+			post = strings.TrimSuffix(post, "/meta")
+			format = formatJSON
+			negotiated = false
+		}
 		if post == "" || post == "posts" {
 			http.Error(w, "no asset provided in path", http.StatusBadRequest)
+			return
 		}
 
-		f, err := PostsFS.Open(post)
+		_, err = fs.Stat(postsFS(), post)
 		var fsErr *fs.PathError
 		switch {
 		case errors.As(err, &fsErr):
+			// the post may have been migrated to the
+			// other post format.
+			if alt, ok := alternatePost(post); ok {
+				u := *r.URL
+				u.Path = "/" + alt + strings.TrimPrefix(r.URL.Path, "/"+post)
+				http.Redirect(w, r, u.String(), http.StatusMovedPermanently)
+				return
+			}
 			http.Error(w, "not found", http.StatusNotFound)
 			return
 		case err != nil:
 			http.Error(w, err.Error(), http.StatusBadGateway)
 			return
 		}
+		if scheduled(post) {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
 
-		// if its not a .post file, its an asset.
+		// if its not a post, its an asset.
 		// so just serve it.
-		if filepath.Ext(post) != ".post" {
-			io.Copy(w, f)
+		if !isPost(post) {
+			if format == formatJSON {
+				http.Error(w, "metadata is only available for posts", http.StatusBadRequest)
+				return
+			}
+			b, err := fs.ReadFile(postsFS(), post)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadGateway)
+				return
+			}
+			w.Header().Set("Cache-Control", cacheControl(post, false))
+			serveEmbedded(w, r, post, b)
 			return
 		}
 
-		var markdown Post
-		err = yaml.NewDecoder(f).Decode(&markdown)
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusBadGateway)
+		// metadata requests are not views of the post.
+		if format != formatJSON {
+			metrics.postView(post)
+		}
+		if negotiated {
+			addVary(w.Header(), "Accept")
+		}
+
+		var b []byte
+		switch format {
+		case formatHTML:
+			b, err = RenderPost(post)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadGateway)
+				return
+			}
+			w.Header().Set("Content-Type", "text/html; charset=UTF-8")
+			serveEmbedded(w, r, generatedPostPath(post), b)
 			return
+		case formatJSON:
+			detail, err := NewPostDetail(post)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadGateway)
+				return
+			}
+			b, err = json.Marshal(detail)
+			if err != nil {
+				http.Error(w, "failed serializing: "+err.Error(), http.StatusInternalServerError)
+				return
+			}
+			// the previous and next posts change as
+			// scheduled posts are revealed.
+			w.Header().Set("Content-Type", "application/json")
+			serveContentAt(w, r, post, "", publishedModTime(), b)
+			return
+		default:
+			markdown, err := readPost(postsFS(), post)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadGateway)
+				return
+			}
+			b = []byte(markdown.MarkDown.Value)
+			w.Header().Set("Content-Type", "text/markdown; charset=UTF-8")
 		}
 
-		w.Header().Add("Content-Type", "text/markdown; charset=UTF-8")
-		w.Write([]byte(markdown.MarkDown.Value))
-		return
+		serveContent(w, r, post, post+"#"+format, b)
+	}
+}
+
+// alternatePost returns the path of the post at p in
+// the other post format if it exists and is published.
+func alternatePost(p string) (string, bool) {
+	var alt string
+	switch path.Ext(p) {
+	case PostExt:
+		alt = strings.TrimSuffix(p, PostExt) + MarkdownExt
+	case MarkdownExt:
+		alt = strings.TrimSuffix(p, MarkdownExt) + PostExt
+	default:
+		return "", false
+	}
+	if _, err := fs.Stat(postsFS(), alt); err != nil {
+		return "", false
+	}
+	// redirecting to a scheduled post would reveal it.
+	if scheduled(alt) {
+		return "", false
+	}
+	return alt, true
+}
+
+// isPost reports whether p is a served post, Markdown
+// files without front matter are assets.
+func isPost(p string) bool {
+	if !IsPostFile(p) {
+		return false
+	}
+	for _, post := range postsCache() {
+		if post.Path == p {
+			return true
+		}
+	}
+	return false
+}
+
+// scheduled reports whether the post at p is scheduled
+// to be published later.
+func scheduled(p string) bool {
+	now := time.Now()
+	for _, post := range postsCache() {
+		if post.Path == p {
+			return post.Scheduled(now)
+		}
+	}
+	return false
+}
+
+// postFormat determines which format a post should be
+// served in.
+func postFormat(r *http.Request) (string, error) {
+	switch r.URL.Query().Get("format") {
+	case "html":
+		return formatHTML, nil
+	case "json":
+		return formatJSON, nil
+	case "markdown", "md":
+		return formatMarkdown, nil
+	case "":
+	default:
+		return "", errors.New("unsupported format param: " + r.URL.Query().Get("format"))
+	}
+	if strings.Contains(r.Header.Get("Accept"), "text/html") {
+		return formatHTML, nil
 	}
+	return formatMarkdown, nil
 }
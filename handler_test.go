@@ -0,0 +1,602 @@
+package goblog_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ldelossa/goblog"
+	"github.com/ldelossa/goblog/test"
+)
+
+// TestSummaryHandlerPaging confirms following the Link header's
+// next page visits every summary exactly once.
+func TestSummaryHandlerPaging(t *testing.T) {
+	goblog.EmbeddedPostsCache = test.GenPosts(5)
+	next := regexp.MustCompile(`<([^>]+)>; rel="next"`)
+
+	got := []string{}
+	target := "/summaries?limit=2"
+	for target != "" {
+		rec := httptest.NewRecorder()
+		goblog.SummaryHandler()(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("got: %v, want: %v", rec.Code, http.StatusOK)
+		}
+		test.CmpEqual(t, rec.Header().Get("X-Total-Count"), "5")
+
+		var page []goblog.Post
+		if err := json.NewDecoder(rec.Body).Decode(&page); err != nil {
+			t.Fatalf("failed decoding page: %v", err)
+		}
+		for _, post := range page {
+			got = append(got, post.Title)
+		}
+
+		target = ""
+		if m := next.FindStringSubmatch(rec.Header().Get("Link")); m != nil {
+			target = m[1]
+		}
+	}
+	test.CmpEqual(t, got, []string{"5", "4", "3", "2", "1"})
+}
+
+func TestSummaryHandlerFilters(t *testing.T) {
+	goblog.EmbeddedPostsCache = test.GenPosts(5)
+	after := goblog.EmbeddedPostsCache[3].Published.UTC().Format(time.RFC3339Nano)
+	before := goblog.EmbeddedPostsCache[0].Published.UTC().Format(time.RFC3339Nano)
+
+	table := []struct {
+		Name  string
+		Query string
+		Code  int
+		Want  []string
+	}{
+		{Name: "Bare Array", Query: "", Code: http.StatusOK, Want: []string{"5", "4", "3", "2", "1"}},
+		{Name: "Offset", Query: "?offset=3", Code: http.StatusOK, Want: []string{"2", "1"}},
+		{Name: "Before And After", Query: "?before=" + before + "&after=" + after, Code: http.StatusOK, Want: []string{"4", "3"}},
+		{Name: "Month Without Year", Query: "?month=1", Code: http.StatusBadRequest},
+		{Name: "Bad Cursor", Query: "?cursor=nope", Code: http.StatusBadRequest},
+	}
+	for _, tt := range table {
+		t.Run(tt.Name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			goblog.SummaryHandler()(rec, httptest.NewRequest(http.MethodGet, "/summaries"+tt.Query, nil))
+			if rec.Code != tt.Code {
+				t.Fatalf("got: %v, want: %v", rec.Code, tt.Code)
+			}
+			if tt.Code != http.StatusOK {
+				return
+			}
+			var page []goblog.Post
+			if err := json.NewDecoder(rec.Body).Decode(&page); err != nil {
+				t.Fatalf("failed decoding page: %v", err)
+			}
+			got := []string{}
+			for _, post := range page {
+				got = append(got, post.Title)
+			}
+			test.CmpEqual(t, got, tt.Want)
+		})
+	}
+}
+
+// TestConditionalGet confirms a request carrying the
+// ETag of a previous response is answered with a 304.
+func TestConditionalGet(t *testing.T) {
+	goblog.EmbeddedPostsCache = test.GenPosts(5)
+
+	rec := httptest.NewRecorder()
+	goblog.SummaryHandler()(rec, httptest.NewRequest(http.MethodGet, "/summaries?limit=2", nil))
+	tag := rec.Header().Get("ETag")
+	if tag == "" {
+		t.Fatalf("no ETag returned")
+	}
+	if rec.Header().Get("Last-Modified") == "" {
+		t.Fatalf("no Last-Modified returned")
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/summaries?limit=2", nil)
+	req.Header.Set("If-None-Match", tag)
+	rec = httptest.NewRecorder()
+	goblog.SummaryHandler()(rec, req)
+	if rec.Code != http.StatusNotModified {
+		t.Fatalf("got: %v, want: %v", rec.Code, http.StatusNotModified)
+	}
+}
+
+func TestWebHandlerHeaders(t *testing.T) {
+	rec := httptest.NewRecorder()
+	goblog.WebHandler(nil)(rec, httptest.NewRequest(http.MethodGet, "/empty.html", nil))
+	test.CmpEqual(t, rec.Code, http.StatusOK)
+	test.CmpEqual(t, rec.Header().Get("Content-Type"), "text/html; charset=utf-8")
+	test.CmpEqual(t, rec.Header().Get("Cache-Control"), "public, max-age=3600")
+}
+
+// TestCORSPolicy confirms a policy restricted to the API paths
+// locks them to the allowed origins while other paths stay open.
+func TestCORSPolicy(t *testing.T) {
+	policy := goblog.CORSPolicy{
+		AllowedOrigins: []string{"https://blog.example.com"},
+		Paths:          []string{"/summaries", "/posts/"},
+	}
+	h := policy.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	table := []struct {
+		path, origin, want string
+	}{
+		{"/summaries", "https://blog.example.com", "https://blog.example.com"},
+		{"/summaries", "https://evil.example.com", ""},
+		{"/posts/hello.post", "https://evil.example.com", ""},
+		{"/index.html", "https://evil.example.com", "*"},
+	}
+	for _, tt := range table {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		req.Header.Set("Origin", tt.origin)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		test.CmpEqual(t, rec.Header().Get("Access-Control-Allow-Origin"), tt.want)
+	}
+}
+
+// TestCORSPolicyCredentials confirms credentials are only
+// shared with the listed origins, however the policy was
+// configured.
+func TestCORSPolicyCredentials(t *testing.T) {
+	table := []struct {
+		origins     []string
+		origin      string
+		credentials string
+	}{
+		{nil, "https://evil.example.com", ""},
+		{[]string{"*"}, "https://evil.example.com", ""},
+		{[]string{"https://blog.example.com"}, "https://blog.example.com", "true"},
+		{[]string{"https://blog.example.com"}, "https://evil.example.com", ""},
+	}
+	for _, tt := range table {
+		policy := goblog.CORSPolicy{AllowedOrigins: tt.origins, AllowCredentials: true}
+		h := policy.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		req := httptest.NewRequest(http.MethodGet, "/summaries", nil)
+		req.Header.Set("Origin", tt.origin)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		test.CmpEqual(t, rec.Header().Get("Access-Control-Allow-Credentials"), tt.credentials)
+	}
+}
+
+// TestAccessLogHandler confirms requests are logged with their
+// status and size and client request IDs are propagated.
+func TestAccessLogHandler(t *testing.T) {
+	var buf bytes.Buffer
+	h, err := goblog.AccessLogHandler(&buf, goblog.LogJSON, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+		w.Write([]byte(r.Header.Get("X-Request-ID")))
+	}))
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/summaries?limit=1", nil)
+	req.Header.Set("X-Request-ID", "abc123")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	test.CmpEqual(t, rec.Header().Get("X-Request-ID"), "abc123")
+	test.CmpEqual(t, rec.Body.String(), "abc123")
+
+	var entry struct {
+		RequestID string `json:"request_id"`
+		URI       string `json:"uri"`
+		Status    int    `json:"status"`
+		Bytes     int    `json:"bytes"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("failed decoding log line %q: %v", buf.String(), err)
+	}
+	test.CmpEqual(t, entry.RequestID, "abc123")
+	test.CmpEqual(t, entry.URI, "/summaries?limit=1")
+	test.CmpEqual(t, entry.Status, http.StatusTeapot)
+	test.CmpEqual(t, entry.Bytes, 6)
+
+	// a request without an ID is assigned one.
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if len(rec.Header().Get("X-Request-ID")) != 32 {
+		t.Fatalf("got request ID: %q, want a generated one", rec.Header().Get("X-Request-ID"))
+	}
+
+	if _, err := goblog.AccessLogHandler(&buf, "xml", h); err == nil {
+		t.Fatalf("expected an error for an unknown format")
+	}
+}
+
+// TestMetricsHandler confirms instrumented requests are
+// exposed by the metrics endpoint.
+func TestMetricsHandler(t *testing.T) {
+	h := goblog.InstrumentHandler("test", http.NotFoundHandler())
+	for i := 0; i < 2; i++ {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/missing", nil))
+	}
+
+	rec := httptest.NewRecorder()
+	goblog.MetricsHandler()(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	for _, want := range []string{
+		`goblog_build_info{version="` + goblog.Version + `"`,
+		`goblog_http_requests_total{handler="test",code="404"} 2`,
+		`goblog_http_request_duration_seconds_count{handler="test"} 2`,
+		`goblog_http_not_found_total{handler="test"} 2`,
+	} {
+		if !strings.Contains(rec.Body.String(), want) {
+			t.Fatalf("got: %s, want line containing: %s", rec.Body.String(), want)
+		}
+	}
+}
+
+// TestReadyzHandler confirms readiness follows SetReady.
+func TestReadyzHandler(t *testing.T) {
+	defer goblog.SetReady(false)
+	for _, tt := range []struct {
+		ready bool
+		want  int
+	}{
+		{false, http.StatusServiceUnavailable},
+		{true, http.StatusOK},
+	} {
+		goblog.SetReady(tt.ready)
+		rec := httptest.NewRecorder()
+		goblog.ReadyzHandler()(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+		test.CmpEqual(t, rec.Code, tt.want)
+	}
+}
+
+func TestRedirectHandler(t *testing.T) {
+	goblog.EmbeddedPostsCache = test.GenPosts(2)
+	goblog.EmbeddedPostsCache[0].Aliases = []string{"/posts/older_title.post", "/posts/old_title.post"}
+	// a new post at a path another post was renamed
+	// away from is served, not redirected.
+	goblog.EmbeddedPostsCache[1].Path = "posts/old_title.post"
+	goblog.Conf.Redirects = map[string]string{"/about": "/posts/about.post"}
+	defer func() { goblog.Conf.Redirects = nil }()
+	// rebuild the redirects from the above.
+	goblog.ServeEmbedded()
+
+	h := goblog.RedirectHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+	table := []struct {
+		Target   string
+		Code     int
+		Location string
+	}{
+		{"/about", http.StatusMovedPermanently, "/posts/about.post"},
+		{"/posts/older_title.post?format=html", http.StatusMovedPermanently, "/" + goblog.EmbeddedPostsCache[0].Path + "?format=html"},
+		{"/posts/older_title.post/meta", http.StatusMovedPermanently, "/" + goblog.EmbeddedPostsCache[0].Path + "/meta"},
+		{"/posts/old_title.post", http.StatusTeapot, ""},
+	}
+	for _, tt := range table {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.Target, nil))
+		test.CmpEqual(t, rec.Code, tt.Code)
+		test.CmpEqual(t, rec.Header().Get("Location"), tt.Location)
+	}
+}
+
+// serveLocalPosts serves the posts, keyed by file name, from
+// a local tree for the duration of a test.
+func serveLocalPosts(t *testing.T, posts map[string]string) {
+	t.Helper()
+	cleanup, _, err := test.HijackEnviroment("posts", "drafts", "web")
+	if err != nil {
+		t.Fatalf("could not hijack environment: %v", err)
+	}
+	t.Cleanup(cleanup)
+	for name, post := range posts {
+		if err := os.WriteFile(filepath.Join(goblog.Posts, name), []byte(post), 0660); err != nil {
+			t.Fatalf("%v", err)
+		}
+	}
+	if err := goblog.ServeLocal(context.Background()); err != nil {
+		t.Fatalf("failed serving local tree: %v", err)
+	}
+	t.Cleanup(goblog.ServeEmbedded)
+}
+
+func TestPostDetailDefaults(t *testing.T) {
+	// a post written before slugs and updated times.
+	serveLocalPosts(t, map[string]string{
+		"old_post.md": "---\ntitle: Old Post\nsummary: s\ndate: 2021-05-28T00:00:00Z\n---\nbody\n",
+	})
+
+	rec := httptest.NewRecorder()
+	goblog.PostsHandler()(rec, httptest.NewRequest(http.MethodGet, "/posts/old_post.md/meta", nil))
+	test.CmpEqual(t, rec.Code, http.StatusOK)
+
+	var detail goblog.PostDetail
+	if err := json.NewDecoder(rec.Body).Decode(&detail); err != nil {
+		t.Fatalf("failed decoding: %v", err)
+	}
+	published := time.Date(2021, 5, 28, 0, 0, 0, 0, time.UTC)
+	test.CmpEqual(t, detail.Slug, "old-post")
+	test.CmpEqual(t, detail.Updated.Equal(published), true)
+	test.CmpEqual(t, detail.Created.Equal(published), true)
+}
+
+func TestCompressHandlerVary(t *testing.T) {
+	h := goblog.CompressHandler(goblog.WebHandler(nil))
+	req := httptest.NewRequest(http.MethodGet, "/empty.html", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	test.CmpEqual(t, rec.Header().Values("Vary"), []string{"Accept-Encoding"})
+}
+
+// TestPostsHandlerNegotiation confirms the post's format is
+// negotiated from the Accept header and caches are told so.
+func TestPostsHandlerNegotiation(t *testing.T) {
+	serveLocalPosts(t, map[string]string{
+		"hello.md": "---\ntitle: Hello\nsummary: s\ndate: 2021-05-28T00:00:00Z\n---\n# Hello\n",
+	})
+
+	table := []struct {
+		Target      string
+		Accept      string
+		ContentType string
+		Vary        string
+	}{
+		{"/posts/hello.md", "text/html,application/xhtml+xml", "text/html; charset=UTF-8", "Accept, Accept-Encoding"},
+		{"/posts/hello.md", "*/*", "text/markdown; charset=UTF-8", "Accept"},
+		{"/posts/hello.md?format=md", "text/html", "text/markdown; charset=UTF-8", ""},
+		{"/posts/hello.md?format=html", "", "text/html; charset=UTF-8", "Accept-Encoding"},
+		{"/posts/hello.md/meta", "text/html", "application/json", ""},
+	}
+	for _, tt := range table {
+		req := httptest.NewRequest(http.MethodGet, tt.Target, nil)
+		req.Header.Set("Accept", tt.Accept)
+		rec := httptest.NewRecorder()
+		goblog.PostsHandler()(rec, req)
+		test.CmpEqual(t, rec.Code, http.StatusOK)
+		test.CmpEqual(t, rec.Header().Get("Content-Type"), tt.ContentType)
+		test.CmpEqual(t, strings.Join(rec.Header().Values("Vary"), ", "), tt.Vary)
+	}
+}
+
+// TestCacheControlFingerprinted confirms only assets in the
+// fingerprint Manifest are cached as immutable.
+func TestCacheControlFingerprinted(t *testing.T) {
+	goblog.SetManifest(goblog.Manifest{"/app.js": "/app.3f2a9c1b.js"})
+	defer goblog.SetManifest(goblog.Manifest{})
+
+	table := []struct {
+		Name string
+		Want string
+	}{
+		{"generated/fingerprinted/app.3f2a9c1b.js", "public, max-age=31536000, immutable"},
+		{"generated/fingerprinted/index.html", "public, max-age=3600"},
+		{"web/hero-20230101.png", "public, max-age=3600"},
+		{"web/photo.deadbeef.jpg", "public, max-age=3600"},
+		{"posts/app.3f2a9c1b.js", "public, max-age=3600"},
+	}
+	for _, tt := range table {
+		test.CmpEqual(t, goblog.CacheControl(tt.Name, false), tt.Want)
+	}
+}
+
+// TestScheduledPostsHidden confirms a post scheduled for later
+// is hidden by every handler listing or serving posts.
+func TestScheduledPostsHidden(t *testing.T) {
+	publishAt := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	serveLocalPosts(t, map[string]string{
+		"visible.md":   "---\ntitle: Visible\nsummary: s\ndate: 2021-05-28T00:00:00Z\ntags: [go]\n---\nshared body\n",
+		"scheduled.md": "---\ntitle: Scheduled\nsummary: s\ndate: " + publishAt + "\npublish_at: " + publishAt + "\ntags: [go, later]\naliases: [/old-scheduled]\n---\nshared body\n",
+	})
+
+	get := func(h http.HandlerFunc, target string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		return rec
+	}
+
+	for _, target := range []string{"/posts/scheduled.md", "/posts/scheduled.md?format=html", "/posts/scheduled.md/meta", "/posts/scheduled.post"} {
+		test.CmpEqual(t, get(goblog.PostsHandler(), target).Code, http.StatusNotFound)
+	}
+	// nor is the post revealed by redirects to it.
+	redirect := goblog.RedirectHandler(http.NotFoundHandler())
+	for _, target := range []string{"/old-scheduled", "/old-scheduled/meta"} {
+		test.CmpEqual(t, get(redirect.ServeHTTP, target).Code, http.StatusNotFound)
+	}
+	test.CmpEqual(t, get(goblog.TagsHandler(), "/tags/later").Code, http.StatusNotFound)
+
+	rec := get(goblog.SummaryHandler(), "/summaries")
+	test.CmpEqual(t, rec.Header().Get("X-Total-Count"), "1")
+
+	var info goblog.VersionInfo
+	if err := json.NewDecoder(get(goblog.VersionHandler(), "/version").Body).Decode(&info); err != nil {
+		t.Fatalf("failed decoding: %v", err)
+	}
+	test.CmpEqual(t, info.Posts, 1)
+
+	rec = get(goblog.PostsHandler(), "/posts/visible.md/meta")
+	var detail goblog.PostDetail
+	if err := json.NewDecoder(rec.Body).Decode(&detail); err != nil {
+		t.Fatalf("failed decoding: %v", err)
+	}
+	test.CmpEqual(t, detail.Next, "")
+
+	for name, rec := range map[string]*httptest.ResponseRecorder{
+		"summaries": get(goblog.SummaryHandler(), "/summaries"),
+		"tags":      get(goblog.TagsHandler(), "/tags"),
+		"rss":       get(goblog.RSSHandler(), "/feed.rss"),
+		"atom":      get(goblog.AtomHandler(), "/feed.atom"),
+		"json feed": get(goblog.JSONFeedHandler(), "/feed.json"),
+		"sitemap":   get(goblog.SitemapHandler(nil), "/sitemap.xml"),
+		"search":    get(goblog.SearchHandler(), "/search?q=shared"),
+	} {
+		test.CmpEqual(t, rec.Code, http.StatusOK)
+		body := rec.Body.String()
+		if !strings.Contains(body, "visible.md") && !strings.Contains(body, `"go"`) {
+			t.Fatalf("%v: published post missing: %s", name, body)
+		}
+		if strings.Contains(body, "scheduled.md") || strings.Contains(body, "later") {
+			t.Fatalf("%v: scheduled post listed: %s", name, body)
+		}
+	}
+}
+
+// TestRevealedPostsModified confirms content listing posts is
+// reported modified when a scheduled post is revealed, so
+// conditional requests don't keep a stale listing.
+func TestRevealedPostsModified(t *testing.T) {
+	build := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+	revealed := build.Add(30 * time.Minute)
+	defer func(v string) { goblog.BuildTime = v }(goblog.BuildTime)
+	goblog.BuildTime = build.Format(time.RFC3339)
+
+	serveLocalPosts(t, map[string]string{
+		"revealed.md": "---\ntitle: Revealed\nsummary: s\ndate: " + revealed.Format(time.RFC3339) + "\npublish_at: " + revealed.Format(time.RFC3339) + "\n---\nbody\n",
+	})
+
+	for _, tt := range []struct {
+		h      http.HandlerFunc
+		target string
+	}{
+		{goblog.SummaryHandler(), "/summaries"},
+		{goblog.RSSHandler(), "/feed.rss"},
+		{goblog.SitemapHandler(nil), "/sitemap.xml"},
+		{goblog.PostsHandler(), "/posts/revealed.md/meta"},
+	} {
+		req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+		req.Header.Set("If-Modified-Since", build.Format(http.TimeFormat))
+		rec := httptest.NewRecorder()
+		tt.h(rec, req)
+		test.CmpEqual(t, rec.Code, http.StatusOK)
+		test.CmpEqual(t, rec.Header().Get("Last-Modified"), revealed.Format(http.TimeFormat))
+	}
+}
+
+func TestTagsHandler(t *testing.T) {
+	serveLocalPosts(t, map[string]string{
+		"first.md":  "---\ntitle: First\nsummary: s\ndate: 2021-05-28T00:00:00Z\ntags: [Go, web]\n---\nbody\n",
+		"second.md": "---\ntitle: Second\nsummary: s\ndate: 2021-06-28T00:00:00Z\ntags: [go]\n---\nbody\n",
+	})
+
+	rec := httptest.NewRecorder()
+	goblog.TagsHandler()(rec, httptest.NewRequest(http.MethodGet, "/tags", nil))
+	test.CmpEqual(t, rec.Code, http.StatusOK)
+	var counts []goblog.TagCount
+	if err := json.NewDecoder(rec.Body).Decode(&counts); err != nil {
+		t.Fatalf("failed decoding: %v", err)
+	}
+	test.CmpEqual(t, counts, []goblog.TagCount{{Tag: "go", Count: 2}, {Tag: "web", Count: 1}})
+
+	table := []struct {
+		Target string
+		Code   int
+		Paths  []string
+	}{
+		{"/tags/go", http.StatusOK, []string{"second.md", "first.md"}},
+		{"/tags/GO/", http.StatusOK, []string{"second.md", "first.md"}},
+		{"/tags/Web", http.StatusOK, []string{"first.md"}},
+		{"/tags/rust", http.StatusNotFound, nil},
+	}
+	for _, tt := range table {
+		rec := httptest.NewRecorder()
+		goblog.TagsHandler()(rec, httptest.NewRequest(http.MethodGet, tt.Target, nil))
+		test.CmpEqual(t, rec.Code, tt.Code)
+		if tt.Code != http.StatusOK {
+			continue
+		}
+		var posts []goblog.Post
+		if err := json.NewDecoder(rec.Body).Decode(&posts); err != nil {
+			t.Fatalf("%v: failed decoding: %v", tt.Target, err)
+		}
+		var paths []string
+		for _, post := range posts {
+			paths = append(paths, filepath.Base(post.Path))
+		}
+		test.CmpEqual(t, paths, tt.Paths)
+	}
+
+	rec = httptest.NewRecorder()
+	goblog.TagsHandler()(rec, httptest.NewRequest(http.MethodPost, "/tags", nil))
+	test.CmpEqual(t, rec.Code, http.StatusMethodNotAllowed)
+}
+
+func TestPostDetail(t *testing.T) {
+	long := strings.Repeat("word ", 401)
+	serveLocalPosts(t, map[string]string{
+		"oldest.md":   "---\ntitle: Oldest\nsummary: s\ndate: 2021-01-01T00:00:00Z\n---\n\n",
+		"middle.md":   "---\ntitle: Middle\nsummary: s\ndate: 2021-02-01T00:00:00Z\n---\n" + long + "\n",
+		"newest.md":   "---\ntitle: Newest\nsummary: s\ndate: 2021-03-01T00:00:00Z\n---\none two three\n",
+		"diagram.png": "\x89PNG\r\n\x1a\n",
+	})
+
+	table := []struct {
+		Post        string
+		Previous    string
+		Next        string
+		WordCount   int
+		ReadingTime int
+	}{
+		{"oldest.md", "", "posts/middle.md", 0, 1},
+		{"middle.md", "posts/oldest.md", "posts/newest.md", 401, 3},
+		{"newest.md", "posts/middle.md", "", 3, 1},
+	}
+	for _, tt := range table {
+		rec := httptest.NewRecorder()
+		goblog.PostsHandler()(rec, httptest.NewRequest(http.MethodGet, "/posts/"+tt.Post+"/meta", nil))
+		test.CmpEqual(t, rec.Code, http.StatusOK)
+		var detail goblog.PostDetail
+		if err := json.NewDecoder(rec.Body).Decode(&detail); err != nil {
+			t.Fatalf("%v: failed decoding: %v", tt.Post, err)
+		}
+		test.CmpEqual(t, detail.Previous, tt.Previous)
+		test.CmpEqual(t, detail.Next, tt.Next)
+		test.CmpEqual(t, detail.WordCount, tt.WordCount)
+		test.CmpEqual(t, detail.ReadingTime, tt.ReadingTime)
+	}
+
+	// metadata is only available for posts, assets are
+	// served as is.
+	rec := httptest.NewRecorder()
+	goblog.PostsHandler()(rec, httptest.NewRequest(http.MethodGet, "/posts/diagram.png/meta", nil))
+	test.CmpEqual(t, rec.Code, http.StatusBadRequest)
+	rec = httptest.NewRecorder()
+	goblog.PostsHandler()(rec, httptest.NewRequest(http.MethodGet, "/posts/diagram.png", nil))
+	test.CmpEqual(t, rec.Code, http.StatusOK)
+}
+
+// TestMarkdownAsset confirms a Markdown file without front
+// matter alongside the posts is served as an asset rather
+// than failing the posts cache.
+func TestMarkdownAsset(t *testing.T) {
+	serveLocalPosts(t, map[string]string{
+		"post.md":   "---\ntitle: Post\nsummary: s\ndate: 2021-05-28T00:00:00Z\n---\nbody\n",
+		"README.md": "# Notes\n\nnot a post\n",
+	})
+
+	posts, err := goblog.NewLocalPostsCache(context.Background())
+	if err != nil {
+		t.Fatalf("failed creating posts cache: %v", err)
+	}
+	test.CmpEqual(t, len(posts), 1)
+	test.CmpEqual(t, posts[0].Path, "posts/post.md")
+
+	rec := httptest.NewRecorder()
+	goblog.PostsHandler()(rec, httptest.NewRequest(http.MethodGet, "/posts/README.md", nil))
+	test.CmpEqual(t, rec.Code, http.StatusOK)
+	test.CmpEqual(t, rec.Body.String(), "# Notes\n\nnot a post\n")
+	rec = httptest.NewRecorder()
+	goblog.PostsHandler()(rec, httptest.NewRequest(http.MethodGet, "/posts/README.md/meta", nil))
+	test.CmpEqual(t, rec.Code, http.StatusBadRequest)
+
+	rec = httptest.NewRecorder()
+	goblog.SummaryHandler()(rec, httptest.NewRequest(http.MethodGet, "/summaries", nil))
+	test.CmpEqual(t, rec.Header().Get("X-Total-Count"), "1")
+}
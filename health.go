@@ -0,0 +1,79 @@
+package goblog
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+)
+
+// ready is non-zero while the server is accepting
+// requests.
+var ready int32
+
+// SetReady marks the server ready, or not, to accept
+// requests as reported by ReadyzHandler.
+func SetReady(r bool) {
+	var v int32
+	if r {
+		v = 1
+	}
+	atomic.StoreInt32(&ready, v)
+}
+
+// VersionInfo describes the running goblog binary and
+// the content embedded in it.
+type VersionInfo struct {
+	Version   string `json:"version"`
+	BuildTime string `json:"build_time,omitempty"`
+	// the number of posts readers can see, scheduled
+	// posts are counted once published.
+	Posts  int `json:"posts"`
+	Drafts int `json:"drafts"`
+}
+
+// HealthzHandler reports the server is alive.
+func HealthzHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "no-store")
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Write([]byte("ok\n"))
+	}
+}
+
+// ReadyzHandler reports whether the server is ready to accept
+// requests, answering 503 Service Unavailable while it starts
+// or shuts down.
+func ReadyzHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "no-store")
+		if atomic.LoadInt32(&ready) == 0 {
+			http.Error(w, "not ready", http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Write([]byte("ok\n"))
+	}
+}
+
+// VersionHandler serves the VersionInfo of the running
+// binary.
+func VersionHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		info := VersionInfo{
+			Version:   Version,
+			BuildTime: BuildTime,
+			Posts:     len(publishedPosts()),
+			Drafts:    len(EmbeddedDraftsCache),
+		}
+		w.Header().Set("Cache-Control", "no-store")
+		w.Header().Set("Content-Type", "application/json")
+		err := json.NewEncoder(w).Encode(info)
+		if err != nil {
+			http.Error(w, "failed serializing: "+err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
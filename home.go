@@ -32,6 +32,10 @@ var (
 	// Configs is a directory which holds GoBlog's embedded
 	// configuration.
 	Configs string
+	// Generated is a directory which holds content GoBlog
+	// derives from your posts and web root at build time,
+	// such as pre-rendered HTML.
+	Generated string
 )
 
 func init() {
@@ -57,6 +61,7 @@ func init() {
 	Drafts = path.Join(Src, "drafts")
 	Web = path.Join(Src, "web")
 	Configs = path.Join(Src, "config")
+	Generated = path.Join(Src, "generated")
 
 	var err error
 	EmbeddedPostsCache, err = NewEmbeddedPostsCache()
@@ -0,0 +1,101 @@
+package goblog
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+const (
+	jsonFeedVersion = "https://jsonfeed.org/version/1.1"
+)
+
+// jsonFeed is the root of a JSON Feed 1.1 document.
+//
+// see: https://www.jsonfeed.org/version/1.1/
+type jsonFeed struct {
+	Version     string           `json:"version"`
+	Title       string           `json:"title"`
+	HomePageURL string           `json:"home_page_url,omitempty"`
+	FeedURL     string           `json:"feed_url,omitempty"`
+	Description string           `json:"description,omitempty"`
+	Authors     []jsonFeedAuthor `json:"authors,omitempty"`
+	Items       []jsonFeedItem   `json:"items"`
+}
+
+type jsonFeedAuthor struct {
+	Name string `json:"name,omitempty"`
+	URL  string `json:"url,omitempty"`
+}
+
+type jsonFeedItem struct {
+	ID            string   `json:"id"`
+	URL           string   `json:"url"`
+	Title         string   `json:"title"`
+	Summary       string   `json:"summary,omitempty"`
+	ContentText   string   `json:"content_text"`
+	Image         string   `json:"image,omitempty"`
+	DatePublished string   `json:"date_published"`
+	DateModified  string   `json:"date_modified"`
+	Tags          []string `json:"tags,omitempty"`
+}
+
+// JSONFeedHandler serves a JSON Feed 1.1 document of the
+// embedded posts.
+//
+// Each item carries the post's Markdown body as its
+// content_text.
+func JSONFeedHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		doc := jsonFeed{
+			Version:     jsonFeedVersion,
+			Title:       Conf.Title,
+			HomePageURL: absURL(r, "/"),
+			FeedURL:     absURL(r, r.URL.Path),
+			Description: Conf.Description,
+			Items:       []jsonFeedItem{},
+		}
+		if Conf.Author != "" {
+			doc.Authors = []jsonFeedAuthor{{Name: Conf.Author}}
+		}
+
+		for _, post := range publishedPosts() {
+			p, err := readPost(postsFS(), post.Path)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+
+			link := absURL(r, post.Path)
+			item := jsonFeedItem{
+				ID:            link,
+				URL:           link,
+				Title:         post.Title,
+				Summary:       post.Summary,
+				ContentText:   p.MarkDown.Value,
+				DatePublished: post.Published.Format(time.RFC3339),
+				DateModified:  post.Updated.Format(time.RFC3339),
+				Tags:          categories(post),
+			}
+			if hero, ok := heroURL(r, post); ok {
+				item.Image = hero
+			}
+			doc.Items = append(doc.Items, item)
+		}
+
+		w.Header().Set("Content-Type", "application/feed+json; charset=UTF-8")
+		var buf bytes.Buffer
+		err := json.NewEncoder(&buf).Encode(doc)
+		if err != nil {
+			http.Error(w, "failed serializing: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		serveContentAt(w, r, "feed.json", "", publishedModTime(), buf.Bytes())
+	}
+}
@@ -0,0 +1,330 @@
+package goblog
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// reloadPath is where ReloadHandler is served.
+const reloadPath = "/_goblog/reload"
+
+// reloadScript is injected into HTML served from the local
+// tree, reloading the page when ReloadHandler sends an event.
+const reloadScript = `<script>new EventSource("` + reloadPath + `").addEventListener("reload", function () { location.reload() })</script>`
+
+// served is the content the handlers serve.
+//
+// Handlers serve the embedded content unless ServeLocal was
+// called, after which they serve the local tree.
+var served = struct {
+	sync.RWMutex
+	local bool
+	// the local tree rooted at Src.
+	fsys  fs.FS
+	cache DateSortable
+	// the search index over the local tree, built
+	// on first use.
+	index *SearchIndex
+}{}
+
+// local reports whether the local tree is served.
+func local() bool {
+	served.RLock()
+	defer served.RUnlock()
+	return served.local
+}
+
+// postsFS returns the file system posts are served from.
+func postsFS() fs.FS {
+	served.RLock()
+	defer served.RUnlock()
+	if served.local {
+		return served.fsys
+	}
+	return PostsFS
+}
+
+// webFS returns the file system the web root is served from.
+func webFS() fs.FS {
+	served.RLock()
+	defer served.RUnlock()
+	if served.local {
+		return served.fsys
+	}
+	return WebFS
+}
+
+// generatedFS returns the file system generated content is
+// served from.
+//
+// The local tree's generated content is as old as the last
+// build, so none is served from it.
+func generatedFS() fs.FS {
+	served.RLock()
+	defer served.RUnlock()
+	if served.local {
+		return emptyFS{}
+	}
+	return GeneratedFS
+}
+
+// postsCache returns the metadata of the served posts
+// newest first.
+func postsCache() DateSortable {
+	served.RLock()
+	defer served.RUnlock()
+	if served.local {
+		return served.cache
+	}
+	return EmbeddedPostsCache
+}
+
+// publishedPosts returns the metadata of the served posts
+// which are not scheduled for later, newest first.
+func publishedPosts() DateSortable {
+	return postsCache().Published(time.Now())
+}
+
+// servedSearchIndex returns a SearchIndex over the
+// served posts.
+func servedSearchIndex() (*SearchIndex, error) {
+	if !local() {
+		return EmbeddedSearchIndex()
+	}
+	served.Lock()
+	defer served.Unlock()
+	if served.index == nil {
+		idx, err := NewSearchIndex(served.fsys, served.cache)
+		if err != nil {
+			return nil, err
+		}
+		served.index = idx
+	}
+	return served.index, nil
+}
+
+// emptyFS is a file system holding no files.
+type emptyFS struct{}
+
+func (emptyFS) Open(name string) (fs.File, error) {
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+}
+
+// ServeLocal points the handlers at the local tree rooted at
+// Src, so posts and web root changes are served without
+// building a new GoBlog binary.
+//
+// Local content is served with Cache-Control "no-cache" and
+// HTML is injected with a script reloading the page on events
+// from ReloadHandler.
+func ServeLocal(ctx context.Context) error {
+	served.Lock()
+	served.local = true
+	served.fsys = os.DirFS(Src)
+	served.Unlock()
+	return ReloadLocal(ctx)
+}
+
+// ReloadLocal rebuilds the local caches after the local
+// tree has changed.
+func ReloadLocal(ctx context.Context) error {
+	posts, err := NewLocalPostsCache(ctx)
+	if err != nil {
+		return fmt.Errorf("failed reading local posts: %w", err)
+	}
+	drafts, err := NewLocalDraftsCache(ctx)
+	if err != nil {
+		return fmt.Errorf("failed reading local drafts: %w", err)
+	}
+
+	served.Lock()
+	LocalPostsCache, LocalDraftsCache = posts, drafts
+	served.cache = posts
+	served.index = nil
+	served.Unlock()
+
+	renderCache.Lock()
+	renderCache.m = map[string][]byte{}
+	renderCache.Unlock()
+	etags.Lock()
+	etags.m = map[string]string{}
+	etags.Unlock()
+	resetRedirects()
+	return nil
+}
+
+// WatchLocal polls the local posts, drafts, and web root for
+// changes every interval until ctx is canceled.
+//
+// On a change the local caches are rebuilt and a reload event
+// is sent to clients of ReloadHandler. Clients are disconnected
+// once ctx is canceled.
+func WatchLocal(ctx context.Context, interval time.Duration, onErr func(error)) {
+	defer reloads.close()
+
+	last := snapshot()
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+		cur := snapshot()
+		if equalSnapshots(last, cur) {
+			continue
+		}
+		last = cur
+		if err := ReloadLocal(ctx); err != nil {
+			onErr(err)
+			continue
+		}
+		reloads.notify()
+	}
+}
+
+// fileState is the state of a watched file used to
+// detect changes.
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// snapshot returns the state of every file in the
+// watched directories.
+func snapshot() map[string]fileState {
+	files := map[string]fileState{}
+	for _, dir := range []string{Posts, Drafts, Web} {
+		filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+			// files may disappear mid walk, the next
+			// snapshot will notice.
+			if err != nil || info.IsDir() {
+				return nil
+			}
+			files[p] = fileState{info.ModTime(), info.Size()}
+			return nil
+		})
+	}
+	return files
+}
+
+func equalSnapshots(a, b map[string]fileState) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for p, s := range a {
+		if t, ok := b[p]; !ok || !t.modTime.Equal(s.modTime) || t.size != s.size {
+			return false
+		}
+	}
+	return true
+}
+
+// reloads holds the clients of ReloadHandler.
+var reloads = &broadcaster{subs: map[chan struct{}]bool{}}
+
+// broadcaster notifies its subscribers of reload events.
+type broadcaster struct {
+	mu     sync.Mutex
+	subs   map[chan struct{}]bool
+	closed bool
+}
+
+// subscribe returns a channel receiving reload events which
+// is closed when the broadcaster is.
+func (b *broadcaster) subscribe() chan struct{} {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	ch := make(chan struct{}, 1)
+	if b.closed {
+		close(ch)
+		return ch
+	}
+	b.subs[ch] = true
+	return ch
+}
+
+func (b *broadcaster) unsubscribe(ch chan struct{}) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.subs[ch] {
+		delete(b.subs, ch)
+		close(ch)
+	}
+}
+
+func (b *broadcaster) notify() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.subs {
+		// a pending event already triggers a reload.
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (b *broadcaster) close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	for ch := range b.subs {
+		delete(b.subs, ch)
+		close(ch)
+	}
+}
+
+// ReloadHandler streams a Server-Sent "reload" event each time
+// WatchLocal detects a change to the local tree.
+func ReloadHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+			return
+		}
+		// subscribe before the client learns it's connected,
+		// so no change after is missed.
+		ch := reloads.subscribe()
+		defer reloads.unsubscribe(ch)
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Write([]byte(": connected\n\n"))
+		flusher.Flush()
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case _, ok := <-ch:
+				if !ok {
+					return
+				}
+				w.Write([]byte("event: reload\ndata: {}\n\n"))
+				flusher.Flush()
+			}
+		}
+	}
+}
+
+// injectReloadScript returns the HTML document b with
+// reloadScript added to the end of its body.
+func injectReloadScript(b []byte) []byte {
+	i := bytes.LastIndex(bytes.ToLower(b), []byte("</body>"))
+	if i == -1 {
+		i = len(b)
+	}
+	out := make([]byte, 0, len(b)+len(reloadScript))
+	out = append(out, b[:i]...)
+	out = append(out, reloadScript...)
+	return append(out, b[i:]...)
+}
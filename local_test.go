@@ -0,0 +1,161 @@
+package goblog_test
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ldelossa/goblog"
+	"github.com/ldelossa/goblog/test"
+)
+
+// TestWatchLocal confirms changes to the local tree rebuild the
+// served posts and send a reload event to every client, and that
+// clients are disconnected once watching stops.
+func TestWatchLocal(t *testing.T) {
+	serveLocalPosts(t, map[string]string{
+		"first.md": "---\ntitle: First\nsummary: s\ndate: 2021-05-28T00:00:00Z\n---\nbody\n",
+	})
+	goblog.ResetReloads()
+	defer goblog.ResetReloads()
+
+	const interval = 10 * time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		goblog.WatchLocal(ctx, interval, func(err error) {
+			t.Errorf("failed reloading: %v", err)
+		})
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	srv := httptest.NewServer(goblog.ReloadHandler())
+	defer srv.Close()
+
+	// readEvent returns the next event, or an error once
+	// the stream ends.
+	readEvent := func(r *bufio.Reader) (string, error) {
+		type result struct {
+			event string
+			err   error
+		}
+		ch := make(chan result, 1)
+		go func() {
+			var lines []string
+			for {
+				line, err := r.ReadString('\n')
+				if err != nil {
+					ch <- result{err: err}
+					return
+				}
+				line = strings.TrimSuffix(line, "\n")
+				if line == "" {
+					ch <- result{event: strings.Join(lines, "\n")}
+					return
+				}
+				lines = append(lines, line)
+			}
+		}()
+		select {
+		case res := <-ch:
+			return res.event, res.err
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for an event")
+		}
+		return "", nil
+	}
+
+	var clients []*bufio.Reader
+	for i := 0; i < 2; i++ {
+		resp, err := http.Get(srv.URL)
+		if err != nil {
+			t.Fatalf("failed connecting: %v", err)
+		}
+		defer resp.Body.Close()
+		test.CmpEqual(t, resp.Header.Get("Content-Type"), "text/event-stream")
+		r := bufio.NewReader(resp.Body)
+		event, err := readEvent(r)
+		if err != nil {
+			t.Fatalf("failed reading: %v", err)
+		}
+		test.CmpEqual(t, event, ": connected")
+		clients = append(clients, r)
+	}
+
+	summaries := func() string {
+		rec := httptest.NewRecorder()
+		goblog.SummaryHandler()(rec, httptest.NewRequest(http.MethodGet, "/summaries", nil))
+		return rec.Header().Get("X-Total-Count")
+	}
+	test.CmpEqual(t, summaries(), "1")
+
+	// let the watcher take its first snapshot.
+	time.Sleep(2 * interval)
+	err := os.WriteFile(filepath.Join(goblog.Posts, "second.md"), []byte("---\ntitle: Second\nsummary: s\ndate: 2021-06-28T00:00:00Z\n---\nbody\n"), 0660)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	for _, r := range clients {
+		event, err := readEvent(r)
+		if err != nil {
+			t.Fatalf("failed reading: %v", err)
+		}
+		test.CmpEqual(t, event, "event: reload\ndata: {}")
+	}
+	test.CmpEqual(t, summaries(), "2")
+
+	cancel()
+	<-done
+	for _, r := range clients {
+		if _, err := readEvent(r); err != io.EOF {
+			t.Fatalf("got: %v, want: %v", err, io.EOF)
+		}
+	}
+}
+
+// TestReloadScript confirms local HTML is served with the
+// script reloading it on changes.
+func TestReloadScript(t *testing.T) {
+	serveLocalPosts(t, nil)
+
+	table := []struct {
+		Name   string
+		HTML   string
+		Before string
+		After  string
+	}{
+		{"body.html", "<html><body><p>hi</p></body></html>", "<html><body><p>hi</p>", "</body></html>"},
+		{"upper.html", "<HTML><BODY><p>hi</p></BODY></HTML>", "<HTML><BODY><p>hi</p>", "</BODY></HTML>"},
+		{"fragment.html", "<p>hi</p>", "<p>hi</p>", ""},
+	}
+	for _, tt := range table {
+		if err := os.WriteFile(filepath.Join(goblog.Web, tt.Name), []byte(tt.HTML), 0660); err != nil {
+			t.Fatalf("%v", err)
+		}
+		rec := httptest.NewRecorder()
+		goblog.WebHandler(nil)(rec, httptest.NewRequest(http.MethodGet, "/"+tt.Name, nil))
+		test.CmpEqual(t, rec.Code, http.StatusOK)
+
+		// the script is injected once, before the end of
+		// the body if there is one.
+		body := rec.Body.String()
+		if !strings.HasPrefix(body, tt.Before+"<script>") || !strings.HasSuffix(body, "</script>"+tt.After) {
+			t.Fatalf("%v: got: %s", tt.Name, body)
+		}
+		test.CmpEqual(t, strings.Count(body, "<script>"), 1)
+		if !strings.Contains(body, `"/_goblog/reload"`) {
+			t.Fatalf("%v: reload script missing: %s", tt.Name, body)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package goblog
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// latencyBuckets are the upper bounds, in seconds, of the
+// request latency histogram buckets.
+var latencyBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// metrics holds the counters exposed by MetricsHandler.
+var metrics = newRegistry()
+
+// registry records request and post view metrics.
+type registry struct {
+	mu        sync.Mutex
+	requests  map[requestKey]uint64
+	latencies map[string]*histogram
+	notFound  map[string]uint64
+	views     map[string]uint64
+}
+
+// requestKey identifies the requests served by a
+// handler with a given status code.
+type requestKey struct {
+	handler string
+	code    int
+}
+
+// histogram is a cumulative histogram over
+// latencyBuckets.
+type histogram struct {
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+func newRegistry() *registry {
+	return &registry{
+		requests:  map[requestKey]uint64{},
+		latencies: map[string]*histogram{},
+		notFound:  map[string]uint64{},
+		views:     map[string]uint64{},
+	}
+}
+
+func (m *registry) observe(handler string, code int, d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requests[requestKey{handler, code}]++
+	if code == http.StatusNotFound {
+		m.notFound[handler]++
+	}
+	h, ok := m.latencies[handler]
+	if !ok {
+		h = &histogram{counts: make([]uint64, len(latencyBuckets))}
+		m.latencies[handler] = h
+	}
+	secs := d.Seconds()
+	for i, le := range latencyBuckets {
+		if secs <= le {
+			h.counts[i]++
+		}
+	}
+	h.sum += secs
+	h.count++
+}
+
+func (m *registry) postView(p string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.views[p]++
+}
+
+// write writes the metrics to w in the Prometheus text
+// exposition format.
+func (m *registry) write(w io.Writer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	header := func(name, typ, help string) {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+	}
+
+	header("goblog_build_info", "gauge", "The version of the running goblog binary.")
+	fmt.Fprintf(w, "goblog_build_info{version=%s,build_time=%s,goversion=%s} 1\n",
+		quoteLabel(Version), quoteLabel(BuildTime), quoteLabel(runtime.Version()))
+
+	header("goblog_http_requests_total", "counter", "Requests served by handler and status code.")
+	keys := make([]requestKey, 0, len(m.requests))
+	for k := range m.requests {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].handler == keys[j].handler {
+			return keys[i].code < keys[j].code
+		}
+		return keys[i].handler < keys[j].handler
+	})
+	for _, k := range keys {
+		fmt.Fprintf(w, "goblog_http_requests_total{handler=%s,code=\"%d\"} %d\n", quoteLabel(k.handler), k.code, m.requests[k])
+	}
+
+	header("goblog_http_request_duration_seconds", "histogram", "Request latency by handler.")
+	handlers := make([]string, 0, len(m.latencies))
+	for handler := range m.latencies {
+		handlers = append(handlers, handler)
+	}
+	sort.Strings(handlers)
+	for _, handler := range handlers {
+		h := m.latencies[handler]
+		for i, le := range latencyBuckets {
+			fmt.Fprintf(w, "goblog_http_request_duration_seconds_bucket{handler=%s,le=\"%s\"} %d\n",
+				quoteLabel(handler), strconv.FormatFloat(le, 'g', -1, 64), h.counts[i])
+		}
+		fmt.Fprintf(w, "goblog_http_request_duration_seconds_bucket{handler=%s,le=\"+Inf\"} %d\n", quoteLabel(handler), h.count)
+		fmt.Fprintf(w, "goblog_http_request_duration_seconds_sum{handler=%s} %s\n", quoteLabel(handler), strconv.FormatFloat(h.sum, 'g', -1, 64))
+		fmt.Fprintf(w, "goblog_http_request_duration_seconds_count{handler=%s} %d\n", quoteLabel(handler), h.count)
+	}
+
+	header("goblog_http_not_found_total", "counter", "Requests answered with 404 Not Found by handler.")
+	for _, handler := range sortedKeys(m.notFound) {
+		fmt.Fprintf(w, "goblog_http_not_found_total{handler=%s} %d\n", quoteLabel(handler), m.notFound[handler])
+	}
+
+	header("goblog_post_views_total", "counter", "Views of each post by path.")
+	for _, p := range sortedKeys(m.views) {
+		fmt.Fprintf(w, "goblog_post_views_total{path=%s} %d\n", quoteLabel(p), m.views[p])
+	}
+}
+
+// sortedKeys returns the keys of m in sorted order.
+func sortedKeys(m map[string]uint64) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// labelEscaper escapes label values as the exposition
+// format requires.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func quoteLabel(v string) string {
+	return `"` + labelEscaper.Replace(v) + `"`
+}
+
+// InstrumentHandler records the count, status, and latency of
+// requests served by h under the provided handler name.
+func InstrumentHandler(name string, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		sw := &statusWriter{ResponseWriter: w}
+		h.ServeHTTP(sw, r)
+		if sw.status == 0 {
+			sw.status = http.StatusOK
+		}
+		metrics.observe(name, sw.status, time.Since(start))
+	})
+}
+
+// MetricsHandler serves the metrics recorded by InstrumentHandler
+// and PostsHandler in the Prometheus text exposition format.
+func MetricsHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-store")
+		metrics.write(w)
+	}
+}
@@ -0,0 +1,195 @@
+package goblog
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// pageQuery holds the pagination and filtering parameters
+// accepted by SummaryHandler.
+type pageQuery struct {
+	// the maximum number of posts in a page, 0 for
+	// no limit.
+	limit int
+	// the number of filtered posts to skip.
+	offset int
+	// only posts dated before and after these times
+	// are kept when they are non-zero.
+	before time.Time
+	after  time.Time
+	// only posts from this year and month are kept when
+	// they are non-zero.
+	year  int
+	month time.Month
+}
+
+// parsePageQuery parses a pageQuery from the request's
+// query parameters.
+//
+// The "cursor" parameter is an opaque value returned in a
+// previous response's Link header and takes precedence over
+// "offset".
+func parsePageQuery(r *http.Request) (pageQuery, error) {
+	var q pageQuery
+	var err error
+	v := r.URL.Query()
+
+	if tmp := v.Get("limit"); tmp != "" {
+		q.limit, err = strconv.Atoi(tmp)
+		if err != nil || q.limit < 0 {
+			return q, fmt.Errorf("could not parse limit param: %v", tmp)
+		}
+	}
+	if tmp := v.Get("offset"); tmp != "" {
+		q.offset, err = strconv.Atoi(tmp)
+		if err != nil || q.offset < 0 {
+			return q, fmt.Errorf("could not parse offset param: %v", tmp)
+		}
+	}
+	if tmp := v.Get("cursor"); tmp != "" {
+		q.offset, err = decodeCursor(tmp)
+		if err != nil {
+			return q, fmt.Errorf("could not parse cursor param: %v", err)
+		}
+	}
+	if tmp := v.Get("before"); tmp != "" {
+		q.before, err = parseDate(tmp)
+		if err != nil {
+			return q, fmt.Errorf("could not parse before param: %v", err)
+		}
+	}
+	if tmp := v.Get("after"); tmp != "" {
+		q.after, err = parseDate(tmp)
+		if err != nil {
+			return q, fmt.Errorf("could not parse after param: %v", err)
+		}
+	}
+	if tmp := v.Get("year"); tmp != "" {
+		q.year, err = strconv.Atoi(tmp)
+		if err != nil {
+			return q, fmt.Errorf("could not parse year param: %v", tmp)
+		}
+	}
+	if tmp := v.Get("month"); tmp != "" {
+		if q.year == 0 {
+			return q, errors.New("month param requires a year param")
+		}
+		m, err := strconv.Atoi(tmp)
+		if err != nil || m < 1 || m > 12 {
+			return q, fmt.Errorf("could not parse month param: %v", tmp)
+		}
+		q.month = time.Month(m)
+	}
+	return q, nil
+}
+
+// filtered reports whether the query filters posts
+// in any way other than paging through them.
+func (q pageQuery) filtered() bool {
+	return !q.before.IsZero() || !q.after.IsZero() || q.year != 0
+}
+
+// filter returns the posts matching the query's date
+// filters, retaining their order.
+func (q pageQuery) filter(posts DateSortable) DateSortable {
+	if !q.filtered() {
+		return posts
+	}
+	matched := DateSortable{}
+	for _, post := range posts {
+		if !q.before.IsZero() && !post.Published.Before(q.before) {
+			continue
+		}
+		if !q.after.IsZero() && !post.Published.After(q.after) {
+			continue
+		}
+		if q.year != 0 && post.Published.Year() != q.year {
+			continue
+		}
+		if q.month != 0 && post.Published.Month() != q.month {
+			continue
+		}
+		matched = append(matched, post)
+	}
+	return matched
+}
+
+// page returns the slice of posts the query's offset
+// and limit select.
+func (q pageQuery) page(posts DateSortable) DateSortable {
+	if q.offset >= len(posts) {
+		return DateSortable{}
+	}
+	end := len(posts)
+	if q.limit > 0 && q.offset+q.limit < end {
+		end = q.offset + q.limit
+	}
+	return posts[q.offset:end]
+}
+
+// links returns the value of a Link header pointing to the
+// next and previous pages of a paged request.
+//
+// An empty string is returned if the request was not paged.
+func (q pageQuery) links(r *http.Request, total int) string {
+	if q.limit == 0 {
+		return ""
+	}
+	var links []string
+	link := func(offset int, rel string) {
+		v := r.URL.Query()
+		v.Del("offset")
+		v.Set("cursor", encodeCursor(offset))
+		u := url.URL{Path: r.URL.Path, RawQuery: v.Encode()}
+		links = append(links, fmt.Sprintf(`<%s>; rel="%s"`, u.String(), rel))
+	}
+	if q.offset+q.limit < total {
+		link(q.offset+q.limit, "next")
+	}
+	if q.offset > 0 {
+		prev := q.offset - q.limit
+		if prev < 0 {
+			prev = 0
+		}
+		link(prev, "prev")
+	}
+	return strings.Join(links, ", ")
+}
+
+// encodeCursor returns an opaque cursor for the
+// provided offset.
+func encodeCursor(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte("o:" + strconv.Itoa(offset)))
+}
+
+// decodeCursor returns the offset held by a cursor
+// created with encodeCursor.
+func decodeCursor(cursor string) (int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, errors.New("malformed cursor")
+	}
+	if !strings.HasPrefix(string(b), "o:") {
+		return 0, errors.New("malformed cursor")
+	}
+	offset, err := strconv.Atoi(strings.TrimPrefix(string(b), "o:"))
+	if err != nil || offset < 0 {
+		return 0, errors.New("malformed cursor")
+	}
+	return offset, nil
+}
+
+// parseDate parses either an RFC3339 timestamp
+// or a YYYY-MM-DD date.
+func parseDate(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", s)
+}
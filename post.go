@@ -1,7 +1,11 @@
 package goblog
 
 import (
+	"fmt"
+	"path"
 	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"gopkg.in/yaml.v3"
@@ -16,13 +20,126 @@ func (t DateSortable) Len() int {
 }
 
 func (t DateSortable) Less(i, j int) bool {
-	return t[i].Date.After(t[j].Date)
+	return t[i].Date().After(t[j].Date())
 }
 
 func (t DateSortable) Swap(i, j int) {
 	t[i], t[j] = t[j], t[i]
 }
 
+// Tagged returns the posts tagged with the provided
+// tag, retaining their date order.
+//
+// Tags are matched case insensitively.
+func (t DateSortable) Tagged(tag string) DateSortable {
+	tagged := DateSortable{}
+	for _, post := range t {
+		if post.HasTag(tag) {
+			tagged = append(tagged, post)
+		}
+	}
+	return tagged
+}
+
+// Lookup returns the post addressed by ref, either its Slug
+// or its 1-based position in t as listed by the CLI.
+//
+// Slugs derived for posts written before slugs were persisted
+// may collide, a slug shared by several posts is an error
+// listing their ids.
+func (t DateSortable) Lookup(ref string) (Post, error) {
+	var matches []int
+	for i, post := range t {
+		if post.Slug == ref {
+			matches = append(matches, i)
+		}
+	}
+	switch {
+	case len(matches) == 1:
+		return t[matches[0]], nil
+	case len(matches) > 1:
+		candidates := make([]string, 0, len(matches))
+		for _, i := range matches {
+			candidates = append(candidates, fmt.Sprintf("%d (%s)", i+1, t[i].Path))
+		}
+		return Post{}, fmt.Errorf("slug %q is shared by several posts, use one of their ids: %s", ref, strings.Join(candidates, ", "))
+	}
+	// slugs are never numeric, see Slugify.
+	if id, err := strconv.Atoi(ref); err == nil && id > 0 && id <= len(t) {
+		return t[id-1], nil
+	}
+	return Post{}, fmt.Errorf("no post with slug or id %q", ref)
+}
+
+// LastUpdated returns the latest Updated time of the
+// posts in t.
+func (t DateSortable) LastUpdated() time.Time {
+	var last time.Time
+	for _, post := range t {
+		if post.Updated.After(last) {
+			last = post.Updated
+		}
+	}
+	return last
+}
+
+// Published returns the posts published by now, hiding
+// scheduled posts and retaining date order.
+func (t DateSortable) Published(now time.Time) DateSortable {
+	var published DateSortable
+	for i, post := range t {
+		if !post.Scheduled(now) {
+			if published != nil {
+				published = append(published, post)
+			}
+			continue
+		}
+		// copy on the first scheduled post, most of
+		// the time there are none.
+		if published == nil {
+			published = append(DateSortable{}, t[:i]...)
+		}
+	}
+	if published == nil {
+		return t
+	}
+	return published
+}
+
+// TagCount is the number of posts carrying
+// a tag.
+type TagCount struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
+
+// TagCounts returns the number of posts carrying each tag,
+// ordered by the most used tag first.
+func (t DateSortable) TagCounts() []TagCount {
+	counts := map[string]int{}
+	for _, post := range t {
+		seen := map[string]bool{}
+		for _, tag := range post.Tags {
+			tag = strings.ToLower(tag)
+			if !seen[tag] {
+				seen[tag] = true
+				counts[tag]++
+			}
+		}
+	}
+	tags := make([]TagCount, 0, len(counts))
+	for tag, n := range counts {
+		tags = append(tags, TagCount{Tag: tag, Count: n})
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		if tags[i].Count == tags[j].Count {
+			return tags[i].Tag < tags[j].Tag
+		}
+		return tags[i].Count > tags[j].Count
+	})
+	return tags
+}
+
 // PathSorted returns a slice of pointers to
 // the DateSortable posts but sorted by
 // Path.
@@ -75,11 +192,76 @@ func (t PathSortableP) Swap(i, j int) {
 type Post struct {
 	// internally used; the path in the embed.FS where the
 	// contents the post can be read.
-	Path    string    `json:"path" yaml:"-"`
-	Hero    string    `json:"hero" yaml:"hero"`
-	Title   string    `json:"title" yaml:"title"`
-	Summary string    `json:"summary" yaml:"summary"`
-	Date    time.Time `json:"date" yaml:"date"`
+	Path  string `json:"path" yaml:"-"`
+	Hero  string `json:"hero" yaml:"hero"`
+	Title string `json:"title" yaml:"title"`
+	// Slug identifies the post on the command line, it is
+	// derived from the title once and never changes.
+	Slug    string `json:"slug,omitempty" yaml:"slug,omitempty"`
+	Summary string `json:"summary" yaml:"summary"`
+	// Published is when the post was published, it keeps
+	// the "date" key of posts written before it was split
+	// from Updated. Zero for drafts never published.
+	Published time.Time `json:"date" yaml:"date,omitempty"`
+	// Updated is when the post was last edited and Created
+	// is when it was started as a draft. Both default to
+	// Published for posts which do not record them.
+	Updated time.Time `json:"updated" yaml:"updated,omitempty"`
+	Created time.Time `json:"created" yaml:"created,omitempty"`
+	// Tags and Category group related posts together.
+	Tags     []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Category string   `json:"category,omitempty" yaml:"category,omitempty"`
+	// Aliases are the paths the post was previously served
+	// at, such as "/posts/old_title.post". Requests for them
+	// are redirected to the post.
+	Aliases []string `json:"aliases,omitempty" yaml:"aliases,omitempty"`
+	// PublishAt optionally schedules the post, hiding it from
+	// readers until this time.
+	PublishAt time.Time `json:"-" yaml:"publish_at,omitempty"`
 	// the markdown body of the blog post.
 	MarkDown yaml.Node `json:"-" yaml:"mark_down,omitempty"`
 }
+
+// setDefaults fills in the metadata a post written by an
+// earlier GoBlog does not record.
+//
+// The slug is derived from the post's file name and the
+// Updated and Created times default to Published.
+func setDefaults(post *Post) {
+	if post.Slug == "" {
+		base := path.Base(post.Path)
+		post.Slug = Slugify(strings.TrimSuffix(base, path.Ext(base)))
+	}
+	if post.Updated.IsZero() {
+		post.Updated = post.Published
+	}
+	if post.Created.IsZero() {
+		post.Created = post.Published
+	}
+}
+
+// Date returns when the post was published or, for a
+// draft never published, when it was created.
+func (p Post) Date() time.Time {
+	if p.Published.IsZero() {
+		return p.Created
+	}
+	return p.Published
+}
+
+// Scheduled reports whether the post is scheduled to be
+// published after t.
+func (p Post) Scheduled(t time.Time) bool {
+	return p.PublishAt.After(t)
+}
+
+// HasTag reports whether the post is tagged with
+// the provided tag, ignoring case.
+func (p Post) HasTag(tag string) bool {
+	for _, t := range p.Tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
@@ -2,7 +2,9 @@ package goblog_test
 
 import (
 	"sort"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/ldelossa/goblog"
 	"github.com/ldelossa/goblog/test"
@@ -22,8 +24,8 @@ func TestDateSortablePosts(t *testing.T) {
 				prev = post
 				continue
 			}
-			if prev.Date.Before(post.Date) {
-				t.Fatalf("prev: %v, cur: %v", prev.Date, post.Date)
+			if prev.Published.Before(post.Published) {
+				t.Fatalf("prev: %v, cur: %v", prev.Published, post.Published)
 			}
 			prev = post
 		}
@@ -40,8 +42,8 @@ func TestDateSortablePosts(t *testing.T) {
 				prev = post
 				continue
 			}
-			if prev.Date.Before(post.Date) {
-				t.Fatalf("prev: %v, cur: %v", prev.Date, post.Date)
+			if prev.Published.Before(post.Published) {
+				t.Fatalf("prev: %v, cur: %v", prev.Published, post.Published)
 			}
 			prev = post
 		}
@@ -62,3 +64,98 @@ func TestDateSortablePosts(t *testing.T) {
 		}
 	}
 }
+
+func TestDateSortablePublished(t *testing.T) {
+	now := time.Date(2021, 5, 28, 0, 0, 0, 0, time.UTC)
+	ds := goblog.DateSortable{
+		{Path: "scheduled.post", PublishAt: now.Add(time.Hour)},
+		{Path: "due.post", PublishAt: now},
+		{Path: "published.post"},
+	}
+
+	var paths []string
+	for _, post := range ds.Published(now) {
+		paths = append(paths, post.Path)
+	}
+	test.CmpEqual(t, paths, []string{"due.post", "published.post"})
+	test.CmpEqual(t, len(ds.Published(now.Add(-time.Hour))), 1)
+	test.CmpEqual(t, len(ds.Published(now.Add(time.Hour))), 3)
+}
+
+func TestDateSortableLookup(t *testing.T) {
+	ds := goblog.DateSortable{
+		{Path: "posts/b.post", Slug: goblog.Slugify("Hello, World!")},
+		{Path: "posts/a.post", Slug: goblog.Slugify("2021")},
+	}
+	test.CmpEqual(t, ds[0].Slug, "hello-world")
+	test.CmpEqual(t, ds[1].Slug, "post-2021")
+
+	for ref, want := range map[string]string{
+		"hello-world": "posts/b.post",
+		"post-2021":   "posts/a.post",
+		"2":           "posts/a.post",
+	} {
+		post, err := ds.Lookup(ref)
+		if err != nil {
+			t.Fatalf("%v: %v", ref, err)
+		}
+		test.CmpEqual(t, post.Path, want)
+	}
+	for _, ref := range []string{"0", "3", "hello"} {
+		if _, err := ds.Lookup(ref); err == nil {
+			t.Fatalf("%v: expected an error", ref)
+		}
+	}
+
+	// slugs derived from file names may collide.
+	ds = append(ds, goblog.Post{Path: "posts/c++.post", Slug: "c"}, goblog.Post{Path: "posts/c.post", Slug: "c"})
+	_, err := ds.Lookup("c")
+	if err == nil || !strings.Contains(err.Error(), "3 (posts/c++.post), 4 (posts/c.post)") {
+		t.Fatalf("expected an error listing the candidates, got: %v", err)
+	}
+}
+
+func TestPostFileName(t *testing.T) {
+	for title, want := range map[string]string{
+		"My First Post":      "my_first_post.md",
+		"Hello, World!":      "hello_world.md",
+		"../../etc/passwd":   "etc_passwd.md",
+		`C:\Windows\win.ini`: "c_windows_win_ini.md",
+		"a/b":                "a_b.md",
+		"..":                 "post.md",
+	} {
+		test.CmpEqual(t, goblog.PostFileName(title, ".md"), want)
+	}
+}
+
+func TestDateSortableTags(t *testing.T) {
+	ds := goblog.DateSortable{
+		{Path: "a.post", Tags: []string{"Go", "go", "Web"}},
+		{Path: "b.post", Tags: []string{"GO"}},
+		{Path: "c.post", Tags: []string{"web"}},
+		{Path: "d.post"},
+	}
+
+	tests := []struct {
+		tag  string
+		want []string
+	}{
+		{"go", []string{"a.post", "b.post"}},
+		{"Go", []string{"a.post", "b.post"}},
+		{"WEB", []string{"a.post", "c.post"}},
+		{"rust", nil},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		var paths []string
+		for _, post := range ds.Tagged(tt.tag) {
+			paths = append(paths, post.Path)
+		}
+		test.CmpEqual(t, paths, tt.want)
+	}
+
+	test.CmpEqual(t, ds.TagCounts(), []goblog.TagCount{
+		{Tag: "go", Count: 2},
+		{Tag: "web", Count: 2},
+	})
+}
@@ -0,0 +1,72 @@
+package goblog
+
+import (
+	"errors"
+	"strings"
+)
+
+const (
+	// the reading speed used to estimate a post's
+	// reading time.
+	wordsPerMinute = 200
+)
+
+// PostDetail is a single post's metadata and body along
+// with fields computed from them.
+type PostDetail struct {
+	Post
+	// the markdown body of the blog post.
+	MarkDown string `json:"mark_down"`
+	// the number of words in the post's body.
+	WordCount int `json:"word_count"`
+	// the estimated minutes required to read the post.
+	ReadingTime int `json:"reading_time"`
+	// the path of the post published before this one, if any.
+	Previous string `json:"previous,omitempty"`
+	// the path of the post published after this one, if any.
+	Next string `json:"next,omitempty"`
+}
+
+// NewPostDetail returns the PostDetail of the embedded
+// post found at path p.
+//
+// The Previous and Next posts are derived from the
+// date order of the served posts.
+func NewPostDetail(p string) (PostDetail, error) {
+	var detail PostDetail
+
+	posts := publishedPosts()
+	i := -1
+	for j, post := range posts {
+		if post.Path == p {
+			i = j
+			break
+		}
+	}
+	if i == -1 {
+		return detail, errors.New("post not found: " + p)
+	}
+
+	post, err := readPost(postsFS(), p)
+	if err != nil {
+		return detail, err
+	}
+
+	// the cached metadata carries the defaults of
+	// posts written by an earlier GoBlog.
+	detail.Post = summary(posts[i])
+	detail.MarkDown = post.MarkDown.Value
+	detail.WordCount = len(strings.Fields(detail.MarkDown))
+	detail.ReadingTime = (detail.WordCount + wordsPerMinute - 1) / wordsPerMinute
+	if detail.ReadingTime == 0 {
+		detail.ReadingTime = 1
+	}
+	// posts are ordered newest first.
+	if i+1 < len(posts) {
+		detail.Previous = posts[i+1].Path
+	}
+	if i > 0 {
+		detail.Next = posts[i-1].Path
+	}
+	return detail, nil
+}
@@ -3,14 +3,13 @@ package goblog
 import (
 	"context"
 	"embed"
+	"errors"
 	"fmt"
 	"io/fs"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
-
-	"gopkg.in/yaml.v3"
 )
 
 //go:embed posts/*
@@ -43,16 +42,20 @@ func NewEmbeddedPostsCache() (DateSortable, error) {
 		if d.IsDir() {
 			return nil
 		}
-		if filepath.Ext(d.Name()) != ".post" {
+		if !IsPostFile(d.Name()) {
 			return nil
 		}
 		f, err := PostsFS.Open(p)
 		if err != nil {
 			return err
 		}
+		defer f.Close()
 
-		var post Post
-		err = yaml.NewDecoder(f).Decode(&post)
+		post, err := DecodePost(f, p)
+		// Markdown files without front matter are assets.
+		if errors.Is(err, ErrNoFrontMatter) {
+			return nil
+		}
 		if err != nil {
 			return err
 		}
@@ -63,13 +66,9 @@ func NewEmbeddedPostsCache() (DateSortable, error) {
 			return nil
 		}
 
-		sorted = append(sorted, Post{
-			Path:    p,
-			Title:   post.Title,
-			Summary: post.Summary,
-			Date:    post.Date,
-			Hero:    post.Hero,
-		})
+		post.Path = p
+		setDefaults(&post)
+		sorted = append(sorted, summary(post))
 		return nil
 	})
 	if err != nil {
@@ -81,6 +80,26 @@ func NewEmbeddedPostsCache() (DateSortable, error) {
 	return sorted, nil
 }
 
+// readPost opens and decodes the post found at path p
+// in the provided filesystem.
+//
+// The returned Post has its Path set to p.
+func readPost(fsys fs.FS, p string) (Post, error) {
+	var post Post
+	f, err := fsys.Open(p)
+	if err != nil {
+		return post, err
+	}
+	defer f.Close()
+
+	post, err = DecodePost(f, p)
+	if err != nil {
+		return post, fmt.Errorf("failed reading post %v: %v", p, err)
+	}
+	post.Path = p
+	return post, nil
+}
+
 // walks the local "posts" directory for local posts, sorts them by date, and returns
 // a list of them.
 func NewLocalPostsCache(ctx context.Context) (DateSortable, error) {
@@ -98,12 +117,17 @@ func NewLocalPostsCache(ctx context.Context) (DateSortable, error) {
 			return err
 		}
 
-		if filepath.Ext(f.Name()) != ".post" {
+		defer f.Close()
+
+		if !IsPostFile(f.Name()) {
 			return nil
 		}
 
-		var post Post
-		err = yaml.NewDecoder(f).Decode(&post)
+		post, err := DecodePost(f, f.Name())
+		// Markdown files without front matter are assets.
+		if errors.Is(err, ErrNoFrontMatter) {
+			return nil
+		}
 		if err != nil {
 			return fmt.Errorf("failed reading post %v: %v", f.Name(), err)
 		}
@@ -112,6 +136,7 @@ func NewLocalPostsCache(ctx context.Context) (DateSortable, error) {
 			return nil
 		}
 		post.Path = strings.TrimPrefix(path, Src+"/")
+		setDefaults(&post)
 		sorted = append(sorted, post)
 		return nil
 	})
@@ -0,0 +1,123 @@
+package goblog
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// redirects holds the normalized Redirects and post Aliases,
+// built on first use from the served posts.
+//
+// ReloadLocal clears it when the local posts change.
+var redirects = struct {
+	sync.RWMutex
+	built bool
+	// configured redirects and post aliases, keyed
+	// by the requested path.
+	conf    map[string]string
+	aliases map[string]Post
+}{}
+
+// RedirectHandler returns h wrapped to answer requests for the
+// configured Redirects and for the Aliases of served posts with
+// a 301 to their current location.
+//
+// Redirects take precedence over aliases and both take
+// precedence over the content h serves.
+func RedirectHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			h.ServeHTTP(w, r)
+			return
+		}
+		if target, ok := redirectTarget(r.URL.Path); ok {
+			if r.URL.RawQuery != "" && !strings.Contains(target, "?") {
+				target += "?" + r.URL.RawQuery
+			}
+			http.Redirect(w, r, target, http.StatusMovedPermanently)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// redirectTarget returns where a request for the path p is
+// redirected, if anywhere.
+func redirectTarget(p string) (string, bool) {
+	redirects.RLock()
+	built := redirects.built
+	redirects.RUnlock()
+	if !built {
+		buildRedirects()
+	}
+
+	redirects.RLock()
+	defer redirects.RUnlock()
+	p = cleanRedirectPath(p)
+	if to, ok := redirects.conf[p]; ok {
+		return to, true
+	}
+	// aliases also redirect requests for a post's
+	// metadata, see PostsHandler.
+	var meta string
+	if strings.HasSuffix(p, "/meta") {
+		if _, ok := redirects.aliases[strings.TrimSuffix(p, "/meta")]; ok {
+			p, meta = strings.TrimSuffix(p, "/meta"), "/meta"
+		}
+	}
+	post, ok := redirects.aliases[p]
+	// redirecting to a scheduled post would reveal it.
+	if !ok || post.Scheduled(time.Now()) {
+		return "", false
+	}
+	return "/" + post.Path + meta, true
+}
+
+// buildRedirects normalizes the configured Redirects and
+// the Aliases of the served posts.
+//
+// An alias which is the current path of a served post is
+// skipped, the post at that path is served instead. Aliases
+// of scheduled posts are only followed once the post is
+// published.
+func buildRedirects() {
+	posts := postsCache()
+	current := map[string]bool{}
+	for _, post := range posts {
+		current[cleanRedirectPath(post.Path)] = true
+	}
+	aliases := map[string]Post{}
+	for _, post := range posts {
+		for _, alias := range post.Aliases {
+			from := cleanRedirectPath(alias)
+			if _, ok := aliases[from]; ok || current[from] {
+				continue
+			}
+			aliases[from] = post
+		}
+	}
+	conf := map[string]string{}
+	for from, to := range Conf.Redirects {
+		conf[cleanRedirectPath(from)] = to
+	}
+
+	redirects.Lock()
+	redirects.built = true
+	redirects.conf, redirects.aliases = conf, aliases
+	redirects.Unlock()
+}
+
+// resetRedirects clears the normalized redirects, they are
+// rebuilt on next use.
+func resetRedirects() {
+	redirects.Lock()
+	redirects.built = false
+	redirects.conf, redirects.aliases = nil, nil
+	redirects.Unlock()
+}
+
+func cleanRedirectPath(p string) string {
+	return "/" + strings.Trim(p, "/")
+}
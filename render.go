@@ -0,0 +1,86 @@
+package goblog
+
+import (
+	"bytes"
+	"io/fs"
+	"sync"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+)
+
+// markdown is the renderer GoBlog uses to turn a post's
+// Markdown into HTML.
+//
+// Headings receive generated ids so they may be linked to,
+// GFM syntax such as fenced code blocks and tables is supported
+// along with footnotes.
+//
+// The renderer is left in its default safe mode, raw HTML
+// embedded in a post is omitted from the output and links with
+// dangerous schemes such as "javascript:" are dropped.
+var markdown = goldmark.New(
+	goldmark.WithExtensions(
+		extension.GFM,
+		extension.Footnote,
+	),
+	goldmark.WithParserOptions(
+		parser.WithAutoHeadingID(),
+	),
+)
+
+// renderCache holds the rendered HTML of embedded posts
+// keyed by their path in PostsFS.
+//
+// Embedded posts never change for the life of the binary
+// so a post only needs to be rendered once.
+var renderCache = struct {
+	sync.RWMutex
+	m map[string][]byte
+}{m: map[string][]byte{}}
+
+// RenderMarkdown renders the provided Markdown into HTML.
+func RenderMarkdown(md string) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := markdown.Convert([]byte(md), &buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// RenderPost returns the embedded post found at path p
+// rendered as HTML.
+//
+// If the binary embeds pre-rendered HTML for the post it is
+// returned as is, otherwise the post is rendered.
+//
+// Rendered posts are cached, subsequent calls for the same
+// path return the cached HTML.
+func RenderPost(p string) ([]byte, error) {
+	renderCache.RLock()
+	html, ok := renderCache.m[p]
+	renderCache.RUnlock()
+	if ok {
+		return html, nil
+	}
+
+	// prefer the HTML pre-rendered when this binary
+	// was built.
+	html, err := fs.ReadFile(generatedFS(), generatedPostPath(p))
+	if err != nil {
+		post, err := readPost(postsFS(), p)
+		if err != nil {
+			return nil, err
+		}
+		html, err = RenderMarkdown(post.MarkDown.Value)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	renderCache.Lock()
+	renderCache.m[p] = html
+	renderCache.Unlock()
+	return html, nil
+}
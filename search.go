@@ -0,0 +1,327 @@
+package goblog
+
+import (
+	"encoding/json"
+	"html"
+	"io/fs"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// the weights applied to a term depending on
+	// where in the post it was found.
+	titleWeight   = 3.0
+	summaryWeight = 2.0
+	bodyWeight    = 1.0
+
+	// the number of characters displayed around
+	// the first match in a snippet.
+	snippetLen = 160
+)
+
+// SearchIndex is an inverted index over the titles,
+// summaries, and Markdown bodies of a set of posts.
+type SearchIndex struct {
+	docs  []searchDoc
+	terms map[string][]posting
+}
+
+// searchDoc is a post held by a SearchIndex.
+type searchDoc struct {
+	post Post
+	// the post's body with white space collapsed,
+	// used to build snippets.
+	body string
+}
+
+// posting records the weighted frequency of a
+// term in a single document.
+type posting struct {
+	doc    int
+	weight float64
+}
+
+// SearchResult is a post matching a search query.
+type SearchResult struct {
+	Post
+	Score   float64 `json:"score"`
+	Snippet string  `json:"snippet"`
+}
+
+// Highlighter formats the snippet of a SearchResult.
+//
+// Text formats the text surrounding a match and Match
+// formats the matched term.
+type Highlighter struct {
+	Text  func(string) string
+	Match func(string) string
+}
+
+// HTMLHighlighter escapes a snippet for display in HTML and
+// wraps matched terms in <mark> tags.
+var HTMLHighlighter = Highlighter{
+	Text: html.EscapeString,
+	Match: func(s string) string {
+		return "<mark>" + html.EscapeString(s) + "</mark>"
+	},
+}
+
+// embeddedIndex is built from the embedded posts on
+// first use.
+var embeddedIndex struct {
+	once sync.Once
+	idx  *SearchIndex
+	err  error
+}
+
+// EmbeddedSearchIndex returns a SearchIndex over the
+// embedded posts.
+//
+// The index is built on the first call and reused
+// afterwards.
+func EmbeddedSearchIndex() (*SearchIndex, error) {
+	embeddedIndex.once.Do(func() {
+		embeddedIndex.idx, embeddedIndex.err = NewSearchIndex(PostsFS, EmbeddedPostsCache)
+	})
+	return embeddedIndex.idx, embeddedIndex.err
+}
+
+// NewSearchIndex builds a SearchIndex over the provided posts,
+// reading each post's body from fsys.
+func NewSearchIndex(fsys fs.FS, posts DateSortable) (*SearchIndex, error) {
+	idx := &SearchIndex{
+		docs:  make([]searchDoc, 0, len(posts)),
+		terms: map[string][]posting{},
+	}
+	for i, post := range posts {
+		p, err := readPost(fsys, post.Path)
+		if err != nil {
+			return nil, err
+		}
+		body := strings.Join(strings.Fields(p.MarkDown.Value), " ")
+		idx.docs = append(idx.docs, searchDoc{post: summary(post), body: body})
+
+		weights := map[string]float64{}
+		for _, t := range tokenize(post.Title) {
+			weights[t.term] += titleWeight
+		}
+		for _, t := range tokenize(post.Summary) {
+			weights[t.term] += summaryWeight
+		}
+		for _, t := range tokenize(body) {
+			weights[t.term] += bodyWeight
+		}
+		for term, w := range weights {
+			idx.terms[term] = append(idx.terms[term], posting{doc: i, weight: w})
+		}
+	}
+	return idx, nil
+}
+
+// Search returns the posts matching any term in query ordered
+// by relevance.
+//
+// Posts are scored by the weighted frequency of each query term
+// scaled by how rare the term is across all posts. Posts matching
+// more of the query's terms rank higher.
+func (idx *SearchIndex) Search(query string, hl Highlighter) []SearchResult {
+	terms := map[string]bool{}
+	for _, t := range tokenize(query) {
+		terms[t.term] = true
+	}
+	if len(terms) == 0 {
+		return []SearchResult{}
+	}
+
+	scores := map[int]float64{}
+	matched := map[int]int{}
+	for term := range terms {
+		postings := idx.terms[term]
+		if len(postings) == 0 {
+			continue
+		}
+		idf := math.Log(1 + float64(len(idx.docs))/float64(len(postings)))
+		for _, p := range postings {
+			scores[p.doc] += p.weight * idf
+			matched[p.doc]++
+		}
+	}
+
+	results := make([]SearchResult, 0, len(scores))
+	for doc, score := range scores {
+		d := idx.docs[doc]
+		results = append(results, SearchResult{
+			Post:    d.post,
+			Score:   score * float64(matched[doc]) / float64(len(terms)),
+			Snippet: snippet(d, terms, hl),
+		})
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score == results[j].Score {
+			return results[i].Published.After(results[j].Published)
+		}
+		return results[i].Score > results[j].Score
+	})
+	return results
+}
+
+// token is a normalized term and its byte
+// offsets in the tokenized text.
+type token struct {
+	term       string
+	start, end int
+}
+
+// tokenize splits s into lower cased terms
+// made of letters and digits.
+func tokenize(s string) []token {
+	var tokens []token
+	start := -1
+	for i, r := range s {
+		isWord := unicode.IsLetter(r) || unicode.IsDigit(r)
+		switch {
+		case isWord && start == -1:
+			start = i
+		case !isWord && start != -1:
+			tokens = append(tokens, token{strings.ToLower(s[start:i]), start, i})
+			start = -1
+		}
+	}
+	if start != -1 {
+		tokens = append(tokens, token{strings.ToLower(s[start:]), start, len(s)})
+	}
+	return tokens
+}
+
+// snippet returns the portion of a document's body surrounding the
+// first matched term, formatted by hl.
+//
+// If no term matches the body the document's summary is used.
+func snippet(d searchDoc, terms map[string]bool, hl Highlighter) string {
+	text := d.body
+	tokens := tokenize(text)
+	first := -1
+	for _, t := range tokens {
+		if terms[t.term] {
+			first = t.start
+			break
+		}
+	}
+	if first == -1 {
+		text = d.post.Summary
+		tokens = tokenize(text)
+		first = 0
+	}
+
+	// center the window on the first match, snapping
+	// its edges to word boundaries.
+	start := first - snippetLen/3
+	if start < 0 {
+		start = 0
+	}
+	end := start + snippetLen
+	if end > len(text) {
+		end = len(text)
+	}
+	for _, t := range tokens {
+		if t.start < start && t.end > start {
+			start = t.start
+		}
+		if t.start < end && t.end > end {
+			end = t.end
+		}
+	}
+	// outside of words the edges may fall within
+	// multi-byte punctuation.
+	for start > 0 && !utf8.RuneStart(text[start]) {
+		start--
+	}
+	for end < len(text) && !utf8.RuneStart(text[end]) {
+		end--
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+	pos := start
+	for _, t := range tokens {
+		if t.start < start || t.end > end || !terms[t.term] {
+			continue
+		}
+		b.WriteString(hl.Text(text[pos:t.start]))
+		b.WriteString(hl.Match(text[t.start:t.end]))
+		pos = t.end
+	}
+	b.WriteString(hl.Text(text[pos:end]))
+	if end < len(text) {
+		b.WriteString("…")
+	}
+	return b.String()
+}
+
+// SearchHandler serves ranked search results over the embedded
+// posts for the "q" query parameter.
+//
+// The optional "limit" query parameter bounds the number of
+// results returned.
+func SearchHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		q := r.URL.Query().Get("q")
+		if strings.TrimSpace(q) == "" {
+			http.Error(w, "no query provided in q param", http.StatusBadRequest)
+			return
+		}
+
+		var lim int
+		var err error
+		tmp := r.URL.Query().Get("limit")
+		if tmp != "" {
+			lim, err = strconv.Atoi(tmp)
+			if err != nil {
+				http.Error(w, "could not parse limit param: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+
+		idx, err := servedSearchIndex()
+		if err != nil {
+			http.Error(w, "failed building search index: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		results := idx.Search(q, HTMLHighlighter)
+		// posts scheduled for later are indexed
+		// but not yet found.
+		now := time.Now()
+		published := results[:0]
+		for _, res := range results {
+			if !res.Scheduled(now) {
+				published = append(published, res)
+			}
+		}
+		results = published
+		if lim > 0 && lim < len(results) {
+			results = results[:lim]
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		err = json.NewEncoder(w).Encode(results)
+		if err != nil {
+			http.Error(w, "failed serializing: "+err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
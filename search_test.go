@@ -0,0 +1,98 @@
+package goblog_test
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ldelossa/goblog"
+	"github.com/ldelossa/goblog/test"
+)
+
+func TestSearchIndex(t *testing.T) {
+	fsys := fstest.MapFS{
+		"posts/go.post": {Data: []byte(`title: Learning Go
+summary: notes on the go language
+mark_down: "Go has goroutines and channels. Channels are typed."
+`)},
+		"posts/rust.post": {Data: []byte(`title: Learning Rust
+summary: notes on rust
+mark_down: "Rust has no goroutines but it does have channels."
+`)},
+	}
+	now := time.Now()
+	posts := goblog.DateSortable{
+		{Path: "posts/go.post", Title: "Learning Go", Summary: "notes on the go language", Published: now},
+		{Path: "posts/rust.post", Title: "Learning Rust", Summary: "notes on rust", Published: now.Add(-time.Hour)},
+	}
+
+	idx, err := goblog.NewSearchIndex(fsys, posts)
+	if err != nil {
+		t.Fatalf("failed building index: %v", err)
+	}
+
+	table := []struct {
+		Name    string
+		Query   string
+		Want    []string
+		Snippet string
+	}{
+		{
+			Name:    "Title Ranks Higher",
+			Query:   "go",
+			Want:    []string{"Learning Go"},
+			Snippet: "<mark>Go</mark> has goroutines and channels. Channels are typed.",
+		},
+		{
+			Name:    "Multiple Matches",
+			Query:   "CHANNELS",
+			Want:    []string{"Learning Go", "Learning Rust"},
+			Snippet: "Go has goroutines and <mark>channels</mark>. <mark>Channels</mark> are typed.",
+		},
+		{
+			Name:  "No Matches",
+			Query: "python",
+			Want:  []string{},
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.Name, func(t *testing.T) {
+			results := idx.Search(tt.Query, goblog.HTMLHighlighter)
+			got := []string{}
+			for _, res := range results {
+				got = append(got, res.Title)
+			}
+			test.CmpEqual(t, got, tt.Want)
+			if len(results) > 0 {
+				test.CmpEqual(t, results[0].Snippet, tt.Snippet)
+			}
+		})
+	}
+}
+
+func TestSearchSnippetMultiByte(t *testing.T) {
+	dashes := strings.Repeat("—", 100)
+	fsys := fstest.MapFS{
+		"posts/dash.post": {Data: []byte("title: Dashes\nsummary: s\nmark_down: \"" + dashes + " match " + dashes + "\"\n")},
+	}
+	posts := goblog.DateSortable{
+		{Path: "posts/dash.post", Title: "Dashes", Summary: "s", Published: time.Now()},
+	}
+	idx, err := goblog.NewSearchIndex(fsys, posts)
+	if err != nil {
+		t.Fatalf("failed building index: %v", err)
+	}
+
+	results := idx.Search("match", goblog.HTMLHighlighter)
+	test.CmpEqual(t, len(results), 1)
+	snippet := results[0].Snippet
+	if !utf8.ValidString(snippet) {
+		t.Fatalf("snippet is not valid UTF-8: %q", snippet)
+	}
+	if !strings.HasPrefix(snippet, "…—") || !strings.HasSuffix(snippet, "—…") || !strings.Contains(snippet, "<mark>match</mark>") {
+		t.Fatalf("unexpected snippet: %q", snippet)
+	}
+}
@@ -0,0 +1,101 @@
+package goblog
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"path"
+	"time"
+)
+
+// sitemap is the root of a sitemap document.
+//
+// see: https://www.sitemaps.org/protocol.html
+type sitemap struct {
+	XMLName xml.Name     `xml:"http://www.sitemaps.org/schemas/sitemap/0.9 urlset"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+type sitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// SitemapHandler serves an XML sitemap listing the web root,
+// the provided front-end application paths, and each embedded
+// post.
+//
+// If the web root ships its own sitemap.xml it is served
+// instead.
+func SitemapHandler(appPaths []string) http.HandlerFunc {
+	web := WebHandler(appPaths)
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if webFileExists("sitemap.xml") {
+			web(w, r)
+			return
+		}
+
+		posts := publishedPosts()
+		var lastMod string
+		if len(posts) > 0 {
+			lastMod = posts.LastUpdated().Format(time.RFC3339)
+		}
+
+		doc := sitemap{
+			URLs: []sitemapURL{{Loc: absURL(r, "/"), LastMod: lastMod}},
+		}
+		for _, p := range appPaths {
+			doc.URLs = append(doc.URLs, sitemapURL{Loc: absURL(r, p), LastMod: lastMod})
+		}
+		for _, post := range posts {
+			doc.URLs = append(doc.URLs, sitemapURL{
+				Loc:     absURL(r, post.Path),
+				LastMod: post.Updated.Format(time.RFC3339),
+			})
+		}
+
+		w.Header().Set("Content-Type", "application/xml; charset=UTF-8")
+		var buf bytes.Buffer
+		err := writeXML(&buf, doc)
+		if err != nil {
+			http.Error(w, "failed serializing: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		serveContentAt(w, r, "sitemap.xml", "", publishedModTime(), buf.Bytes())
+	}
+}
+
+// RobotsHandler serves a robots.txt allowing all crawlers
+// and pointing them to the sitemap served by SitemapHandler.
+//
+// If the web root ships its own robots.txt it is served
+// instead.
+func RobotsHandler(appPaths []string) http.HandlerFunc {
+	web := WebHandler(appPaths)
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if webFileExists("robots.txt") {
+			web(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/plain; charset=UTF-8")
+		fmt.Fprintf(w, "User-agent: *\nAllow: /\n\nSitemap: %s\n", absURL(r, "/sitemap.xml"))
+	}
+}
+
+// webFileExists reports whether the embedded web root
+// contains the file name.
+func webFileExists(name string) bool {
+	_, err := fs.Stat(webFS(), path.Join("web", name))
+	return err == nil
+}
@@ -0,0 +1,67 @@
+package goblog
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Slugify returns the slug of a post titled title.
+//
+// Letters and digits are lower cased and every other run of
+// characters becomes a single '-'. Slugs are never numeric
+// so they cannot be mistaken for a post's ID.
+func Slugify(title string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(title) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			dash = false
+			b.WriteRune(r)
+			continue
+		}
+		dash = true
+	}
+	slug := b.String()
+	if slug == "" {
+		return "post"
+	}
+	if _, err := strconv.Atoi(slug); err == nil {
+		return "post-" + slug
+	}
+	return slug
+}
+
+// UniqueSlug returns the slug of a post titled title which is
+// not taken by any local or embedded post or draft.
+//
+// A numeric suffix is appended when the title's slug is taken.
+func UniqueSlug(title string) string {
+	taken := map[string]bool{}
+	for _, cache := range []DateSortable{
+		EmbeddedPostsCache, LocalPostsCache,
+		EmbeddedDraftsCache, LocalDraftsCache,
+	} {
+		for _, post := range cache {
+			taken[post.Slug] = true
+		}
+	}
+	base := Slugify(title)
+	slug := base
+	for i := 2; taken[slug]; i++ {
+		slug = base + "-" + strconv.Itoa(i)
+	}
+	return slug
+}
+
+// PostFileName returns the file name of a post titled title
+// in the format indicated by ext.
+//
+// The name is the title's slug with '_' between words, so
+// it never contains a path separator or "..".
+func PostFileName(title, ext string) string {
+	return strings.ReplaceAll(Slugify(title), "-", "_") + ext
+}
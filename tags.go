@@ -0,0 +1,43 @@
+package goblog
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// TagsHandler serves the tags of the embedded posts.
+//
+// A request to "/tags" returns each tag along with the number
+// of posts carrying it.
+//
+// A request to "/tags/{tag}" returns the summaries of the posts
+// carrying {tag} in date order.
+func TagsHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		tag := strings.Trim(strings.TrimPrefix(r.URL.Path, "/tags"), "/")
+
+		var v interface{}
+		if tag == "" {
+			v = publishedPosts().TagCounts()
+		} else {
+			tagged := publishedPosts().Tagged(tag)
+			if len(tagged) == 0 {
+				http.Error(w, "not found", http.StatusNotFound)
+				return
+			}
+			v = tagged
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		err := json.NewEncoder(w).Encode(v)
+		if err != nil {
+			http.Error(w, "failed serializing: "+err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
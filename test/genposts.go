@@ -26,11 +26,11 @@ func GenRandomPosts(n int) []goblog.Post {
 		durationMod := rand.Int() % 121
 		duration := time.Duration(-durationMod) * (24 * time.Hour)
 		posts = append(posts, goblog.Post{
-			Path:    filepath.Join(goblog.Posts, RandomString(4)) + ".post",
-			Hero:    filepath.Join(goblog.Posts, RandomString(4)) + ".png",
-			Title:   RandomString(4) + " " + RandomString(4),
-			Summary: RandomString(4) + " " + RandomString(4),
-			Date:    time.Now().Add(duration),
+			Path:      filepath.Join(goblog.Posts, RandomString(4)) + ".post",
+			Hero:      filepath.Join(goblog.Posts, RandomString(4)) + ".png",
+			Title:     RandomString(4) + " " + RandomString(4),
+			Summary:   RandomString(4) + " " + RandomString(4),
+			Published: time.Now().Add(duration),
 		})
 	}
 	return posts
@@ -54,10 +54,11 @@ func GenPosts(n int) []goblog.Post {
 	for i := 0; i < n; i++ {
 		s := strconv.Itoa(n - i)
 		posts = append(posts, goblog.Post{
-			Path:    filepath.Join("drafts", s),
-			Title:   s,
-			Summary: s,
-			Date:    now.Add(-time.Duration(i) * (24 * time.Hour)),
+			Path:      filepath.Join("drafts", s),
+			Title:     s,
+			Slug:      goblog.Slugify(s),
+			Summary:   s,
+			Published: now.Add(-time.Duration(i) * (24 * time.Hour)),
 		})
 	}
 	return posts
@@ -33,6 +33,7 @@ func HijackEnviroment(mkdir ...string) (func(), string, error) {
 	goblog.Drafts = path.Join(goblog.Src, "drafts")
 	goblog.Web = path.Join(goblog.Src, "web")
 	goblog.Configs = path.Join(goblog.Src, "config")
+	goblog.Generated = path.Join(goblog.Src, "generated")
 
 	for _, dir := range mkdir {
 		var err error
@@ -49,6 +50,8 @@ func HijackEnviroment(mkdir ...string) (func(), string, error) {
 			err = os.MkdirAll(goblog.Web, 0777)
 		case "config":
 			err = os.MkdirAll(goblog.Configs, 0777)
+		case "generated":
+			err = os.MkdirAll(goblog.Generated, 0777)
 		default:
 		}
 		if err != nil {
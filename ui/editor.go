@@ -82,7 +82,7 @@ func (e Editor) open(ctx context.Context, file string) error {
 //
 // Once the user saves the buffer and closes their
 // editor the MarkDown contents will be appended
-// to the in-memory Post and the date will be updated.
+// to the in-memory Post and its Updated time is set.
 //
 // It is expected that the caller persist the
 // Post to disk for perminent storage.
@@ -97,13 +97,15 @@ func (e Editor) Edit(ctx context.Context) error {
 		return ErrNoPath
 	}
 
-	mdTmp := strings.ReplaceAll(e.Post.Path, ".post", ".md")
-	mdTmp = filepath.Join(goblog.Src, mdTmp)
-
-	f, err := os.OpenFile(mdTmp, os.O_CREATE|os.O_RDWR, 0660)
+	// the temporary file is kept out of the posts and drafts
+	// directories, where it would be mistaken for a Markdown
+	// post.
+	ext := filepath.Ext(e.Post.Path)
+	f, err := os.CreateTemp("", strings.TrimSuffix(filepath.Base(e.Post.Path), ext)+"-*.md")
 	if err != nil {
 		return err
 	}
+	mdTmp := f.Name()
 
 	_, err = io.WriteString(f, e.Post.MarkDown.Value)
 	if err != nil {
@@ -147,7 +149,7 @@ func (e Editor) Edit(ctx context.Context) error {
 		Style: yaml.FlowStyle,
 		Value: string(buff),
 	}
-	e.Post.Date = time.Now()
+	e.Post.Updated = time.Now()
 	return nil
 }
 func onErrDumpMarkdown(md string, err error) {
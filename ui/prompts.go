@@ -6,6 +6,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/ldelossa/goblog"
 )
@@ -60,9 +61,11 @@ func NewPrompter() Prompter {
 // the Metadata of a draft Post.
 func (p Prompter) DraftBuilder(ctx context.Context) (goblog.Post, error) {
 	const (
-		TitlePrompt   = "What's the title of this post (required)?\n> "
-		SummaryPrompt = "What's the summary of this post(required)?\n> "
-		HeroPrompt    = "Path to a hero image.\nHero images live in the /posts directory so provide a path such as '/posts/myposthero.png'\nType 'none' for no hero image.\n> "
+		TitlePrompt    = "What's the title of this post (required)?\n> "
+		SummaryPrompt  = "What's the summary of this post(required)?\n> "
+		HeroPrompt     = "Path to a hero image.\nHero images live in the /posts directory so provide a path such as '/posts/myposthero.png'\nType 'none' for no hero image.\n> "
+		TagsPrompt     = "A comma separated list of tags for this post, such as 'go,linux'.\nType 'none' for no tags.\n> "
+		CategoryPrompt = "What's the category of this post?\nType 'none' for no category.\n> "
 	)
 
 	var post goblog.Post
@@ -83,8 +86,36 @@ func (p Prompter) DraftBuilder(ctx context.Context) (goblog.Post, error) {
 		return post, err
 	}
 
-	formatted := strings.ReplaceAll(post.Title, " ", "_")
-	formatted = strings.ToLower(formatted)
+	tags, err := p.prompt(ctx, TagsPrompt)
+	if err != nil {
+		return post, err
+	}
+	if tags != "none" {
+		for _, tag := range strings.Split(tags, ",") {
+			tag = strings.ToLower(strings.TrimSpace(tag))
+			if tag != "" {
+				post.Tags = append(post.Tags, tag)
+			}
+		}
+	}
+
+	post.Category, err = p.prompt(ctx, CategoryPrompt)
+	if err != nil {
+		return post, err
+	}
+	if post.Category == "none" {
+		post.Category = ""
+	}
+
+	// the slug is derived once, editing the title later
+	// must not change how the post is addressed.
+	post.Slug = goblog.UniqueSlug(post.Title)
+
+	// the draft is dated when it is published, until
+	// then Published is zero.
+	post.Created = time.Now()
+	post.Updated = post.Created
+
 	// this is a temporary path used to aide building a
 	// draft.
 	//
@@ -92,7 +123,7 @@ func (p Prompter) DraftBuilder(ctx context.Context) (goblog.Post, error) {
 	// our in-memory caches.
 	//
 	// see: goblog/postsfs.go:66 as an example.
-	post.Path = formatted + ".post"
+	post.Path = goblog.PostFileName(post.Title, goblog.PostExt)
 	return post, nil
 }
 